@@ -0,0 +1,266 @@
+package avro_kafka_config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ClusterResourceName est le nom de la ressource cluster dans les ACL
+const ClusterResourceName = "kafka-cluster"
+
+// ErrACLFilterTooBroad refuse une suppression d'ACL sans ressource ni principal
+var ErrACLFilterTooBroad = errors.New("filtre d'ACL trop large : préciser une ressource ou un principal")
+
+// ACL est une autorisation accordée (ou refusée) à un principal sur une ressource.
+// Les valeurs nulles valent : hôte "*", motif littéral, permission Allow.
+type ACL struct {
+	Principal    string                  `json:"principal" yaml:"principal"` // ex : User:sa-12345
+	Host         string                  `json:"host" yaml:"host"`
+	ResourceType kafka.ResourceType      `json:"resource_type" yaml:"resource_type"`
+	ResourceName string                  `json:"resource_name" yaml:"resource_name"`
+	PatternType  kafka.PatternType       `json:"pattern_type" yaml:"pattern_type"`
+	Operation    kafka.ACLOperationType  `json:"operation" yaml:"operation"`
+	Permission   kafka.ACLPermissionType `json:"permission" yaml:"permission"`
+}
+
+func (a ACL) String() string {
+	return fmt.Sprintf("%s %s %s sur %s:%s (%s)", a.Principal, a.Permission, a.Operation, a.ResourceType, a.ResourceName, a.PatternType)
+}
+
+// withDefaults applique les valeurs par défaut d'une ACL à créer
+func (a ACL) withDefaults() ACL {
+	if a.Host == "" {
+		a.Host = "*"
+	}
+	if a.PatternType == kafka.PatternTypeUnknown {
+		a.PatternType = kafka.PatternTypeLiteral
+	}
+	if a.Permission == kafka.ACLPermissionTypeUnknown {
+		a.Permission = kafka.ACLPermissionTypeAllow
+	}
+	return a
+}
+
+// ProducerACLs retourne les ACL nécessaires pour produire sur `topic`
+func ProducerACLs(principal, topic string) []ACL {
+	return []ACL{
+		{Principal: principal, ResourceType: kafka.ResourceTypeTopic, ResourceName: topic, Operation: kafka.ACLOperationTypeWrite},
+		{Principal: principal, ResourceType: kafka.ResourceTypeTopic, ResourceName: topic, Operation: kafka.ACLOperationTypeDescribe},
+	}
+}
+
+// ConsumerACLs retourne les ACL nécessaires pour consommer `topic` au sein du groupe
+// `group` (aucune ACL de groupe si `group` est vide)
+func ConsumerACLs(principal, topic, group string) []ACL {
+	acls := []ACL{
+		{Principal: principal, ResourceType: kafka.ResourceTypeTopic, ResourceName: topic, Operation: kafka.ACLOperationTypeRead},
+		{Principal: principal, ResourceType: kafka.ResourceTypeTopic, ResourceName: topic, Operation: kafka.ACLOperationTypeDescribe},
+	}
+	if group != "" {
+		acls = append(acls, ACL{Principal: principal, ResourceType: kafka.ResourceTypeGroup, ResourceName: group, Operation: kafka.ACLOperationTypeRead})
+	}
+	return acls
+}
+
+// CreateACLs crée des ACL ; recréer une ACL existante n'est pas une erreur
+func (kc *KafkaClient) CreateACLs(ctx context.Context, acls ...ACL) error {
+	entries := make([]kafka.ACLEntry, 0, len(acls))
+	for _, a := range acls {
+		a = a.withDefaults()
+		entries = append(entries, kafka.ACLEntry{
+			ResourceType:        a.ResourceType,
+			ResourceName:        a.ResourceName,
+			ResourcePatternType: a.PatternType,
+			Principal:           a.Principal,
+			Host:                a.Host,
+			Operation:           a.Operation,
+			PermissionType:      a.Permission,
+		})
+	}
+
+	res, err := kc.client().CreateACLs(ctx, &kafka.CreateACLsRequest{ACLs: entries})
+	if err == nil {
+		err = errors.Join(res.Errors...)
+	}
+	if err != nil {
+		return fmt.Errorf("erreur lors de la création des ACL : %w", err)
+	}
+
+	for _, a := range acls {
+		kc.logger.Info("ACL créée", slog.String("acl", a.withDefaults().String()))
+	}
+	return nil
+}
+
+// ListACLs retourne les ACL correspondant au filtre ; les champs nuls du filtre
+// correspondent à toutes les valeurs
+func (kc *KafkaClient) ListACLs(ctx context.Context, filter ACL) ([]ACL, error) {
+	res, err := kc.client().DescribeACLs(ctx, &kafka.DescribeACLsRequest{Filter: kafka.ACLFilter{
+		ResourceTypeFilter:        orAny(filter.ResourceType, kafka.ResourceTypeUnknown, kafka.ResourceTypeAny),
+		ResourceNameFilter:        filter.ResourceName,
+		ResourcePatternTypeFilter: orAny(filter.PatternType, kafka.PatternTypeUnknown, kafka.PatternTypeAny),
+		PrincipalFilter:           filter.Principal,
+		HostFilter:                filter.Host,
+		Operation:                 orAny(filter.Operation, kafka.ACLOperationTypeUnknown, kafka.ACLOperationTypeAny),
+		PermissionType:            orAny(filter.Permission, kafka.ACLPermissionTypeUnknown, kafka.ACLPermissionTypeAny),
+	}})
+	if err == nil {
+		err = res.Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture des ACL : %w", err)
+	}
+
+	var acls []ACL
+	for _, r := range res.Resources {
+		for _, d := range r.ACLs {
+			acls = append(acls, ACL{
+				Principal:    d.Principal,
+				Host:         d.Host,
+				ResourceType: r.ResourceType,
+				ResourceName: r.ResourceName,
+				PatternType:  r.PatternType,
+				Operation:    d.Operation,
+				Permission:   d.PermissionType,
+			})
+		}
+	}
+	sortACLs(acls)
+	return acls, nil
+}
+
+// DeleteACLs supprime les ACL correspondant aux filtres (champs nuls : toutes
+// les valeurs) et retourne les ACL supprimées. Un filtre doit préciser au moins
+// une ressource ou un principal.
+func (kc *KafkaClient) DeleteACLs(ctx context.Context, filters ...ACL) ([]ACL, error) {
+	req := &kafka.DeleteACLsRequest{Filters: make([]kafka.DeleteACLsFilter, 0, len(filters))}
+	for _, f := range filters {
+		if f.ResourceName == "" && f.Principal == "" {
+			return nil, ErrACLFilterTooBroad
+		}
+		req.Filters = append(req.Filters, kafka.DeleteACLsFilter{
+			ResourceTypeFilter:        orAny(f.ResourceType, kafka.ResourceTypeUnknown, kafka.ResourceTypeAny),
+			ResourceNameFilter:        f.ResourceName,
+			ResourcePatternTypeFilter: orAny(f.PatternType, kafka.PatternTypeUnknown, kafka.PatternTypeAny),
+			PrincipalFilter:           f.Principal,
+			HostFilter:                f.Host,
+			Operation:                 orAny(f.Operation, kafka.ACLOperationTypeUnknown, kafka.ACLOperationTypeAny),
+			PermissionType:            orAny(f.Permission, kafka.ACLPermissionTypeUnknown, kafka.ACLPermissionTypeAny),
+		})
+	}
+
+	res, err := kc.client().DeleteACLs(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la suppression des ACL : %w", err)
+	}
+
+	var deleted []ACL
+	var errs []error
+	for _, r := range res.Results {
+		errs = append(errs, r.Error)
+		for _, m := range r.MatchingACLs {
+			if m.Error != nil {
+				errs = append(errs, m.Error)
+				continue
+			}
+			acl := ACL{
+				Principal:    m.Principal,
+				Host:         m.Host,
+				ResourceType: m.ResourceType,
+				ResourceName: m.ResourceName,
+				PatternType:  m.ResourcePatternType,
+				Operation:    m.Operation,
+				Permission:   m.PermissionType,
+			}
+			kc.logger.Info("ACL supprimée", slog.String("acl", acl.String()))
+			deleted = append(deleted, acl)
+		}
+	}
+	sortACLs(deleted)
+	if err := errors.Join(errs...); err != nil {
+		return deleted, fmt.Errorf("erreur lors de la suppression des ACL : %w", err)
+	}
+	return deleted, nil
+}
+
+// orAny remplace la valeur nulle `unknown` d'un filtre par `any`
+func orAny[T comparable](v, unknown, anyValue T) T {
+	if v == unknown {
+		return anyValue
+	}
+	return v
+}
+
+func sortACLs(acls []ACL) {
+	sort.Slice(acls, func(i, j int) bool { return acls[i].String() < acls[j].String() })
+}
+
+// planACLs compare les producteurs et consommateurs déclarés pour un topic aux ACL
+// du cluster. Les ACL manquantes sont à créer ; les ACL littérales du topic non
+// déclarées sont signalées mais jamais supprimées automatiquement.
+func (kc *KafkaClient) planACLs(ctx context.Context, want TopicManifest) ([]Change, error) {
+	if len(want.Producers) == 0 && len(want.Consumers) == 0 {
+		return nil, nil
+	}
+
+	var desired []ACL
+	for _, principal := range want.Producers {
+		desired = append(desired, ProducerACLs(principal, want.Name)...)
+	}
+	groups := map[string]bool{}
+	for _, c := range want.Consumers {
+		desired = append(desired, ConsumerACLs(c.Principal, want.Name, c.Group)...)
+		if c.Group != "" {
+			groups[c.Group] = true
+		}
+	}
+
+	existing, err := kc.ListACLs(ctx, ACL{ResourceType: kafka.ResourceTypeTopic, ResourceName: want.Name, PatternType: kafka.PatternTypeLiteral})
+	if err != nil {
+		return nil, err
+	}
+	topicACLs := len(existing)
+	for group := range groups {
+		groupACLs, err := kc.ListACLs(ctx, ACL{ResourceType: kafka.ResourceTypeGroup, ResourceName: group, PatternType: kafka.PatternTypeLiteral})
+		if err != nil {
+			return nil, err
+		}
+		existing = append(existing, groupACLs...)
+	}
+
+	present := make(map[ACL]bool, len(existing))
+	for _, a := range existing {
+		present[a] = true
+	}
+	expected := make(map[ACL]bool, len(desired))
+
+	var changes []Change
+	for _, a := range desired {
+		a = a.withDefaults()
+		if expected[a] {
+			continue
+		}
+		expected[a] = true
+		if present[a] {
+			continue
+		}
+		changes = append(changes, Change{
+			Action: ActionCreate, Topic: want.Name, Field: "acl", Detail: a.String(),
+			apply: func(ctx context.Context, kc *KafkaClient) error { return kc.CreateACLs(ctx, a) },
+		})
+	}
+	for _, a := range existing[:topicACLs] {
+		if !expected[a] {
+			changes = append(changes, Change{
+				Action: ActionUnsafe, Topic: want.Name, Field: "acl",
+				Detail: a.String() + " : non déclarée dans le manifeste (à supprimer avec acls delete)",
+			})
+		}
+	}
+	return changes, nil
+}
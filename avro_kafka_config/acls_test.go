@@ -0,0 +1,40 @@
+package avro_kafka_config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConsumerACLs(t *testing.T) {
+	acls := ConsumerACLs("User:sa-1", "orders", "orders-service")
+	require.Len(t, acls, 3)
+	assert.Equal(t, kafka.ResourceTypeGroup, acls[2].ResourceType)
+	assert.Equal(t, kafka.ACLOperationTypeRead, acls[2].Operation)
+
+	assert.Len(t, ConsumerACLs("User:sa-1", "orders", ""), 2)
+}
+
+func TestACL_WithDefaults(t *testing.T) {
+	acl := ProducerACLs("User:sa-1", "orders")[0].withDefaults()
+	assert.Equal(t, "*", acl.Host)
+	assert.Equal(t, kafka.PatternTypeLiteral, acl.PatternType)
+	assert.Equal(t, kafka.ACLPermissionTypeAllow, acl.Permission)
+	assert.Equal(t, "User:sa-1 Allow Write sur Topic:orders (Literal)", acl.String())
+}
+
+func TestACL_YAML(t *testing.T) {
+	out, err := yaml.Marshal(ProducerACLs("User:sa-1", "orders")[0].withDefaults())
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "operation: Write")
+	assert.Contains(t, string(out), "resource_type: Topic")
+}
+
+func TestDeleteACLs_TooBroad(t *testing.T) {
+	_, err := NewKafkaClient(Config{}).DeleteACLs(context.Background(), ACL{Operation: kafka.ACLOperationTypeRead})
+	assert.ErrorIs(t, err, ErrACLFilterTooBroad)
+}
@@ -1,84 +1,81 @@
 package avro_kafka_config
 
 import (
-	"context"
 	"crypto/tls"
-	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
 	"github.com/segmentio/kafka-go/sasl/plain"
 )
 
-// KafkaClient permet de gérer les topics
+// KafkaClient permet de gérer les topics, les groupes de consommateurs et les dead letter queues
 type KafkaClient struct {
 	config Config
+	logger *slog.Logger
 }
 
-// NewKafkaClient crée une instance du client Kafka
-func NewKafkaClient(cfg Config) *KafkaClient {
-	return &KafkaClient{config: cfg}
-}
+// Option personnalise un KafkaClient à sa création
+type Option func(*KafkaClient)
 
-// CreateTopic crée un topic Kafka
-func (kc *KafkaClient) CreateTopic(topic string, partitions int) error {
-	dialer := &kafka.Dialer{
-		SASLMechanism: plain.Mechanism{
-			Username: kc.config.APIKey,
-			Password: kc.config.APISecret,
-		},
-		TLS: &tls.Config{},
+// WithLogger remplace le logger par défaut (slog.Default())
+func WithLogger(logger *slog.Logger) Option {
+	return func(kc *KafkaClient) {
+		kc.logger = logger
 	}
+}
 
-	conn, err := dialer.DialContext(context.Background(), "tcp", kc.config.BootstrapServers)
-	if err != nil {
-		return fmt.Errorf("erreur de connexion à Kafka : %w", err)
+// NewKafkaClient crée une instance du client Kafka
+func NewKafkaClient(cfg Config, opts ...Option) *KafkaClient {
+	kc := &KafkaClient{config: cfg, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(kc)
 	}
-	defer conn.Close()
+	return kc
+}
 
-	err = conn.CreateTopics(kafka.TopicConfig{
-		Topic:             topic,
-		NumPartitions:     partitions,
-		ReplicationFactor: 3,
-	})
-	if err != nil {
-		return fmt.Errorf("erreur lors de la création du topic %s : %w", topic, err)
+// dialer retourne un dialer selon le protocole de sécurité : SASL/PLAIN sur TLS
+// pour Confluent Cloud, en clair pour un broker local sans identifiants
+func (kc *KafkaClient) dialer() *kafka.Dialer {
+	return &kafka.Dialer{
+		SASLMechanism: kc.mechanism(),
+		TLS:           kc.tlsConfig(),
 	}
+}
 
-	log.Printf("Topic %s créé avec succès", topic)
-	return nil
+// transport est l'équivalent de dialer() pour les kafka.Writer et kafka.Client
+func (kc *KafkaClient) transport() *kafka.Transport {
+	return &kafka.Transport{
+		SASL: kc.mechanism(),
+		TLS:  kc.tlsConfig(),
+	}
 }
 
-// ListTopics liste les topics disponibles
-func (kc *KafkaClient) ListTopics() ([]string, error) {
-	dialer := &kafka.Dialer{
-		SASLMechanism: plain.Mechanism{
+// mechanism retourne nil si le protocole n'utilise pas SASL
+func (kc *KafkaClient) mechanism() sasl.Mechanism {
+	switch kc.config.securityProtocol() {
+	case ProtocolSASLPlaintext, ProtocolSASLSSL:
+		return plain.Mechanism{
 			Username: kc.config.APIKey,
 			Password: kc.config.APISecret,
-		},
-		TLS: &tls.Config{},
-	}
-
-	conn, err := dialer.DialContext(context.Background(), "tcp", kc.config.BootstrapServers)
-	if err != nil {
-		return nil, fmt.Errorf("erreur de connexion à Kafka : %w", err)
-	}
-	defer conn.Close()
-
-	partitions, err := conn.ReadPartitions()
-	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la lecture des partitions : %w", err)
+		}
 	}
+	return nil
+}
 
-	topicsMap := make(map[string]bool)
-	for _, p := range partitions {
-		topicsMap[p.Topic] = true
+// tlsConfig retourne nil si le protocole n'utilise pas TLS
+func (kc *KafkaClient) tlsConfig() *tls.Config {
+	switch kc.config.securityProtocol() {
+	case ProtocolSSL, ProtocolSASLSSL:
+		return &tls.Config{}
 	}
+	return nil
+}
 
-	var topics []string
-	for topic := range topicsMap {
-		topics = append(topics, topic)
+// client retourne un client des API d'administration Kafka
+func (kc *KafkaClient) client() *kafka.Client {
+	return &kafka.Client{
+		Addr:      kafka.TCP(kc.config.BootstrapServers),
+		Transport: kc.transport(),
 	}
-
-	return topics, nil
 }
@@ -0,0 +1,29 @@
+package avro_kafka_config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafkaClient_SecurityProtocol(t *testing.T) {
+	// broker local sans identifiants : connexion en clair
+	kc := NewKafkaClient(Config{BootstrapServers: "localhost:9092"})
+	assert.Nil(t, kc.dialer().SASLMechanism)
+	assert.Nil(t, kc.dialer().TLS)
+	assert.Nil(t, kc.transport().SASL)
+	assert.Nil(t, kc.transport().TLS)
+
+	// Confluent Cloud : SASL/PLAIN sur TLS
+	kc = NewKafkaClient(Config{APIKey: "key", APISecret: "secret"})
+	assert.NotNil(t, kc.dialer().SASLMechanism)
+	assert.NotNil(t, kc.dialer().TLS)
+
+	// protocole explicite
+	kc = NewKafkaClient(Config{APIKey: "key", SecurityProtocol: "sasl_plaintext"})
+	assert.NotNil(t, kc.transport().SASL)
+	assert.Nil(t, kc.transport().TLS)
+	kc = NewKafkaClient(Config{SecurityProtocol: ProtocolSSL})
+	assert.Nil(t, kc.transport().SASL)
+	assert.NotNil(t, kc.transport().TLS)
+}
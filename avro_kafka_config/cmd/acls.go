@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_kafka_config"
+	"github.com/segmentio/kafka-go"
+	"github.com/spf13/cobra"
+)
+
+func newACLsCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "acls",
+		Short: "Gestion des ACL (droits des comptes de service sur les topics et groupes)",
+	}
+	cmd.AddCommand(
+		newACLsListCmd(a),
+		newACLsCreateCmd(a),
+		newACLsDeleteCmd(a),
+	)
+	return cmd
+}
+
+// aclFlags regroupe les options de sélection d'une ressource et d'un principal
+type aclFlags struct {
+	principal, host   string
+	topic, group      string
+	cluster, prefixed bool
+	operations        []string
+	deny              bool
+}
+
+func (f *aclFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.principal, "principal", "", "principal (ex : User:sa-12345)")
+	cmd.Flags().StringVar(&f.host, "host", "", "hôte autorisé (défaut : tous)")
+	cmd.Flags().StringVar(&f.topic, "topic", "", "topic concerné")
+	cmd.Flags().StringVar(&f.group, "group", "", "groupe de consommateurs concerné")
+	cmd.Flags().BoolVar(&f.cluster, "cluster", false, "ACL sur le cluster")
+	cmd.Flags().BoolVar(&f.prefixed, "prefixed", false, "le nom de ressource est un préfixe")
+	cmd.Flags().StringArrayVar(&f.operations, "operation", nil, "opération : read, write, describe, create, delete, alter, all… (répétable)")
+	cmd.Flags().BoolVar(&f.deny, "deny", false, "refuse l'opération au lieu de l'autoriser")
+	_ = cmd.RegisterFlagCompletionFunc("operation", cobra.FixedCompletions([]string{
+		"read", "write", "describe", "create", "delete", "alter", "describeconfigs", "alterconfigs", "idempotentwrite", "all",
+	}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// acls retourne les ACL décrites par les options, une par opération (une seule
+// sans opération) ; les champs non renseignés restent nuls
+func (f *aclFlags) acls() ([]avro_kafka_config.ACL, error) {
+	resources := 0
+	for _, set := range []bool{f.topic != "", f.group != "", f.cluster} {
+		if set {
+			resources++
+		}
+	}
+	if resources > 1 {
+		return nil, fmt.Errorf("--topic, --group et --cluster sont exclusifs")
+	}
+
+	base := avro_kafka_config.ACL{Principal: f.principal, Host: f.host}
+	switch {
+	case f.topic != "":
+		base.ResourceType, base.ResourceName = kafka.ResourceTypeTopic, f.topic
+	case f.group != "":
+		base.ResourceType, base.ResourceName = kafka.ResourceTypeGroup, f.group
+	case f.cluster:
+		base.ResourceType, base.ResourceName = kafka.ResourceTypeCluster, avro_kafka_config.ClusterResourceName
+	}
+	if f.prefixed {
+		base.PatternType = kafka.PatternTypePrefixed
+	}
+	if f.deny {
+		base.Permission = kafka.ACLPermissionTypeDeny
+	}
+
+	if len(f.operations) == 0 {
+		return []avro_kafka_config.ACL{base}, nil
+	}
+	acls := make([]avro_kafka_config.ACL, 0, len(f.operations))
+	for _, op := range f.operations {
+		acl := base
+		if err := acl.Operation.UnmarshalText([]byte(op)); err != nil {
+			return nil, fmt.Errorf("opération inconnue %q", op)
+		}
+		acls = append(acls, acl)
+	}
+	return acls, nil
+}
+
+func newACLsListCmd(a *app) *cobra.Command {
+	var flags aclFlags
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Liste les ACL (filtrées par ressource, principal ou opération)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			filters, err := flags.acls()
+			if err != nil {
+				return err
+			}
+			if err := a.load(); err != nil {
+				return err
+			}
+			var acls []avro_kafka_config.ACL
+			for _, filter := range filters {
+				found, err := a.client.ListACLs(cmd.Context(), filter)
+				if err != nil {
+					return err
+				}
+				acls = append(acls, found...)
+			}
+			return a.renderACLs(cmd.OutOrStdout(), acls)
+		},
+	}
+	flags.register(cmd)
+	return cmd
+}
+
+func newACLsCreateCmd(a *app) *cobra.Command {
+	var flags aclFlags
+	var producer, consumer bool
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Crée des ACL pour un principal",
+		Long: "Crée une ACL par --operation sur la ressource choisie. --producer et --consumer créent les ACL " +
+			"nécessaires pour produire sur ou consommer --topic (avec --group pour l'ACL du groupe de consommateurs).",
+		Example: `  avro_kafka_config acls create --principal User:sa-12345 --topic orders --operation read --operation describe
+  avro_kafka_config acls create --principal User:sa-12345 --topic orders --producer
+  avro_kafka_config acls create --principal User:sa-67890 --topic orders --consumer --group orders-service`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var acls []avro_kafka_config.ACL
+			switch {
+			case producer || consumer:
+				if flags.topic == "" {
+					return fmt.Errorf("--producer et --consumer nécessitent --topic")
+				}
+				if producer {
+					acls = append(acls, avro_kafka_config.ProducerACLs(flags.principal, flags.topic)...)
+				}
+				if consumer {
+					acls = append(acls, avro_kafka_config.ConsumerACLs(flags.principal, flags.topic, flags.group)...)
+				}
+			default:
+				if len(flags.operations) == 0 {
+					return fmt.Errorf("préciser au moins une --operation (ou --producer / --consumer)")
+				}
+				if flags.topic == "" && flags.group == "" && !flags.cluster {
+					return fmt.Errorf("préciser la ressource : --topic, --group ou --cluster")
+				}
+				var err error
+				if acls, err = flags.acls(); err != nil {
+					return err
+				}
+			}
+			if err := a.load(); err != nil {
+				return err
+			}
+			if err := a.client.CreateACLs(cmd.Context(), acls...); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%d ACL créée(s)\n", len(acls))
+			return nil
+		},
+	}
+	flags.register(cmd)
+	cmd.Flags().BoolVar(&producer, "producer", false, "ACL nécessaires pour produire sur --topic")
+	cmd.Flags().BoolVar(&consumer, "consumer", false, "ACL nécessaires pour consommer --topic")
+	_ = cmd.MarkFlagRequired("principal")
+	return cmd
+}
+
+func newACLsDeleteCmd(a *app) *cobra.Command {
+	var flags aclFlags
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Supprime les ACL correspondant aux options",
+		Long: "Supprime toutes les ACL correspondant aux options (les options absentes correspondent à toutes " +
+			"les valeurs) ; préciser au moins une ressource ou un principal. Sans --yes, affiche les ACL concernées.",
+		Example: `  avro_kafka_config acls delete --principal User:sa-12345 --topic orders
+  avro_kafka_config acls delete --principal User:sa-12345 --topic orders --yes`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			filters, err := flags.acls()
+			if err != nil {
+				return err
+			}
+			if err := a.load(); err != nil {
+				return err
+			}
+			if !yes {
+				var acls []avro_kafka_config.ACL
+				for _, filter := range filters {
+					if filter.ResourceName == "" && filter.Principal == "" {
+						return avro_kafka_config.ErrACLFilterTooBroad
+					}
+					found, err := a.client.ListACLs(cmd.Context(), filter)
+					if err != nil {
+						return err
+					}
+					acls = append(acls, found...)
+				}
+				if err := a.renderACLs(cmd.OutOrStdout(), acls); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.ErrOrStderr(), "Simulation : confirmer la suppression avec --yes")
+				return nil
+			}
+
+			deleted, err := a.client.DeleteACLs(cmd.Context(), filters...)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%d ACL supprimée(s)\n", len(deleted))
+			return nil
+		},
+	}
+	flags.register(cmd)
+	cmd.Flags().BoolVar(&yes, "yes", false, "confirme la suppression")
+	return cmd
+}
+
+func (a *app) renderACLs(w io.Writer, acls []avro_kafka_config.ACL) error {
+	if acls == nil {
+		acls = []avro_kafka_config.ACL{}
+	}
+	return a.render(w, acls, func(w io.Writer) {
+		fmt.Fprintln(w, "PRINCIPAL\tPERMISSION\tOPÉRATION\tRESSOURCE\tNOM\tMOTIF\tHÔTE")
+		for _, acl := range acls {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", acl.Principal, acl.Permission, acl.Operation,
+				acl.ResourceType, acl.ResourceName, acl.PatternType, acl.Host)
+		}
+	})
+}
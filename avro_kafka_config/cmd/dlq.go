@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_kafka_config"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/consumer"
+	"github.com/segmentio/kafka-go"
+	"github.com/spf13/cobra"
+)
+
+// dlqEntry est la représentation affichée d'un message de dead letter queue
+type dlqEntry struct {
+	Partition int               `json:"partition" yaml:"partition"`
+	Offset    int64             `json:"offset" yaml:"offset"`
+	Time      time.Time         `json:"time" yaml:"time"`
+	Key       string            `json:"key" yaml:"key"`
+	Headers   map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Schema    string            `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Value     any               `json:"value,omitempty" yaml:"value,omitempty"`
+	Error     string            `json:"decode_error,omitempty" yaml:"decode_error,omitempty"`
+}
+
+func newEntry(msg kafka.Message) dlqEntry {
+	entry := dlqEntry{
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Time:      msg.Time,
+		Key:       string(msg.Key),
+		Headers:   make(map[string]string, len(msg.Headers)),
+	}
+	for _, h := range msg.Headers {
+		entry.Headers[h.Key] = string(h.Value)
+	}
+	return entry
+}
+
+func newDLQCmd(a *app) *cobra.Command {
+	var topic string
+	cmd := &cobra.Command{
+		Use:   "dlq",
+		Short: "Inspection et rejeu d'une dead letter queue (ou d'un topic de quarantaine)",
+	}
+	cmd.PersistentFlags().StringVar(&topic, "topic", "", "topic de dead letter queue")
+	_ = cmd.MarkPersistentFlagRequired("topic")
+
+	cmd.AddCommand(
+		newDLQListCmd(a, &topic),
+		newDLQShowCmd(a, &topic),
+		newDLQReplayCmd(a, &topic),
+	)
+	return cmd
+}
+
+func newDLQListCmd(a *app, topic *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Liste les messages de la dead letter queue",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := a.load(); err != nil {
+				return err
+			}
+			var entries []dlqEntry
+			err := a.client.ReadTopic(cmd.Context(), *topic, func(msg kafka.Message) error {
+				entries = append(entries, newEntry(msg))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			return a.renderEntries(cmd.OutOrStdout(), entries)
+		},
+	}
+}
+
+func newDLQShowCmd(a *app, topic *string) *cobra.Command {
+	var partition int
+	var schemaName, manifestPath string
+	cmd := &cobra.Command{
+		Use:   "show <offset>",
+		Short: "Affiche un message et sa valeur décodée",
+		Long: "Affiche les headers et la valeur d'un message. Au format Confluent, la valeur est décodée avec " +
+			"son schéma d'écriture ; sinon avec --schema ou, à défaut, le schéma déclaré dans le manifeste pour " +
+			"son topic d'origine (header original_topic). Le schéma est lu dans avroschemas.AvroSchemas ou, " +
+			"à défaut, est la dernière version du sujet <schéma>-value du Schema Registry.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			offset, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("offset invalide %q", args[0])
+			}
+			if err := a.load(); err != nil {
+				return err
+			}
+			msg, err := a.client.ReadMessage(cmd.Context(), *topic, partition, offset)
+			if err != nil {
+				return err
+			}
+
+			entry := newEntry(msg)
+			entry.Schema = schemaName
+			if entry.Schema == "" {
+				if entry.Schema, err = manifestSchema(manifestPath, entry.Headers[consumer.HeaderOriginalTopic]); err != nil {
+					return err
+				}
+			}
+			entry.Value, err = avro_kafka_config.DecodeValue(cmd.Context(), a.cfg, entry.Schema, msg.Value)
+			if errors.Is(err, avro_kafka_config.ErrUnknownSchema) {
+				err = fmt.Errorf("%w : préciser --schema", err)
+			}
+			if err != nil {
+				entry.Value, entry.Error = fmt.Sprintf("%q", msg.Value), err.Error()
+			}
+
+			return a.render(cmd.OutOrStdout(), entry, func(w io.Writer) {
+				fmt.Fprintf(w, "Partition\t%d\nOffset\t%d\nDate\t%s\nClé\t%s\n", entry.Partition, entry.Offset,
+					entry.Time.Format(time.RFC3339), entry.Key)
+				for _, h := range msg.Headers {
+					fmt.Fprintf(w, "Header %s\t%s\n", h.Key, h.Value)
+				}
+				if entry.Error != "" {
+					fmt.Fprintf(w, "Valeur (non décodée : %s)\t%s\n", entry.Error, entry.Value)
+					return
+				}
+				value, _ := json.MarshalIndent(entry.Value, "", "  ")
+				if entry.Schema == "" {
+					fmt.Fprintf(w, "Valeur\n%s\n", value)
+					return
+				}
+				fmt.Fprintf(w, "Valeur (%s)\n%s\n", entry.Schema, value)
+			})
+		},
+	}
+	cmd.Flags().IntVar(&partition, "partition", 0, "partition du message")
+	cmd.Flags().StringVar(&schemaName, "schema", "", "schéma Avro (par défaut : celui du topic d'origine dans le manifeste)")
+	cmd.Flags().StringVarP(&manifestPath, "file", "f", defaultManifestPath, "manifeste YAML des topics (facultatif)")
+	_ = cmd.RegisterFlagCompletionFunc("schema", completeSchemaNames)
+	return cmd
+}
+
+// manifestSchema retourne le schéma de valeur déclaré pour `topic` dans le
+// manifeste `path` ("" si le topic, ou le manifeste par défaut, est absent)
+func manifestSchema(path, topic string) (string, error) {
+	if topic == "" {
+		return "", nil
+	}
+	manifest, err := avro_kafka_config.LoadManifest(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && path == defaultManifestPath:
+		return "", nil
+	case err != nil:
+		return "", err
+	}
+	for _, t := range manifest.Topics {
+		if t.Name == topic {
+			return t.Schema, nil
+		}
+	}
+	return "", nil
+}
+
+func newDLQReplayCmd(a *app, topic *string) *cobra.Command {
+	var filterExprs []string
+	var to string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Republie les messages sélectionnés sur leur topic d'origine",
+		Long: "Republie les messages sélectionnés par --filter sur leur topic d'origine (header original_topic) " +
+			"ou sur --to, en conservant clé et headers.\n\n" +
+			"Un filtre s'écrit champ<op>valeur ; les champs sont key, partition, offset ou le nom d'un header " +
+			"(error, original_topic…), les opérateurs =, !=, ~ (contient) et, pour partition et offset, >= et <=.",
+		Example: `  avro_kafka_config dlq replay --topic orders.dlq --filter "error~timeout" --filter "offset>=40" --dry-run
+  avro_kafka_config dlq replay --topic orders.dlq --filter "key=user-1" --to orders`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			filter, err := avro_kafka_config.ParseMessageFilter(filterExprs)
+			if err != nil {
+				return err
+			}
+			if err := a.load(); err != nil {
+				return err
+			}
+
+			if dryRun {
+				var entries []dlqEntry
+				err := a.client.ReadTopic(cmd.Context(), *topic, func(msg kafka.Message) error {
+					if filter(msg) {
+						entries = append(entries, newEntry(msg))
+					}
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				return a.renderEntries(cmd.OutOrStdout(), entries)
+			}
+			replayed, err := a.client.Replay(cmd.Context(), *topic, filter, to)
+			if err != nil {
+				return fmt.Errorf("%d message(s) republié(s) avant l'erreur : %w", replayed, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%d message(s) republié(s)\n", replayed)
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVar(&filterExprs, "filter", nil, "filtre champ<op>valeur (répétable)")
+	cmd.Flags().StringVar(&to, "to", "", "topic de destination (par défaut : le topic d'origine de chaque message)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "affiche les messages sélectionnés sans les republier")
+	return cmd
+}
+
+// renderEntries affiche un résumé des messages
+func (a *app) renderEntries(w io.Writer, entries []dlqEntry) error {
+	if entries == nil {
+		entries = []dlqEntry{}
+	}
+	return a.render(w, entries, func(w io.Writer) {
+		fmt.Fprintln(w, "PARTITION\tOFFSET\tDATE\tCLÉ\tTOPIC D'ORIGINE\tERREUR")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\n", e.Partition, e.Offset, e.Time.Format(time.RFC3339),
+				e.Key, e.Headers[consumer.HeaderOriginalTopic], e.Headers[consumer.HeaderError])
+		}
+	})
+}
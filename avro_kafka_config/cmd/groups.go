@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_kafka_config"
+	"github.com/spf13/cobra"
+)
+
+func newGroupsCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "groups",
+		Short: "Administration des groupes de consommateurs (lag, réinitialisation des offsets)",
+	}
+	cmd.AddCommand(
+		newGroupsListCmd(a),
+		newGroupsDescribeCmd(a),
+		newGroupsResetOffsetsCmd(a),
+	)
+	return cmd
+}
+
+func newGroupsListCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Liste les groupes de consommateurs du cluster",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := a.load(); err != nil {
+				return err
+			}
+			groups, err := a.client.ListGroups(cmd.Context())
+			if err != nil {
+				return err
+			}
+			return a.render(cmd.OutOrStdout(), groups, func(w io.Writer) {
+				fmt.Fprintln(w, "GROUPE\tCOORDINATEUR")
+				for _, g := range groups {
+					fmt.Fprintf(w, "%s\t%d\n", g.ID, g.Coordinator)
+				}
+			})
+		},
+	}
+}
+
+func newGroupsDescribeCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:               "describe <groupe>",
+		Short:             "Affiche les membres, les assignations et le lag par partition d'un groupe",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeGroups,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.load(); err != nil {
+				return err
+			}
+			desc, err := a.client.DescribeGroup(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return a.render(cmd.OutOrStdout(), desc, func(w io.Writer) {
+				fmt.Fprintf(w, "Groupe\t%s\nÉtat\t%s\nMembres\t%d\nLag total\t%d\n\n", desc.ID, desc.State, len(desc.Members), desc.Lag())
+				if len(desc.Members) > 0 {
+					fmt.Fprintln(w, "MEMBRE\tCLIENT\tHÔTE\tASSIGNATIONS")
+					for _, m := range desc.Members {
+						fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.ID, m.ClientID, m.Host, formatAssignments(m.Assignments))
+					}
+					fmt.Fprintln(w)
+				}
+				fmt.Fprintln(w, "TOPIC\tPARTITION\tCOMMITÉ\tFIN\tLAG\tMEMBRE")
+				for _, o := range desc.Offsets {
+					fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%s\t%s\n", o.Topic, o.Partition, formatOffset(o.Committed), o.End,
+						formatOffset(o.Lag), o.Member)
+				}
+			})
+		},
+	}
+}
+
+func newGroupsResetOffsetsCmd(a *app) *cobra.Command {
+	var (
+		topics               []string
+		toEarliest, toLatest bool
+		toDatetime           string
+		shiftBy              int64
+		dryRun               bool
+	)
+	cmd := &cobra.Command{
+		Use:   "reset-offsets <groupe>",
+		Short: "Réinitialise les offsets d'un groupe (consommateurs arrêtés)",
+		Long: "Réinitialise les offsets commités du groupe sur --topic (par défaut, tous les topics du groupe). " +
+			"Le groupe doit être vide : arrêter les consommateurs avant d'appliquer. --dry-run affiche les " +
+			"nouveaux offsets sans les commiter.",
+		Example: `  avro_kafka_config groups reset-offsets orders-service --topic orders --to-datetime 2024-05-01T00:00:00Z --dry-run
+  avro_kafka_config groups reset-offsets orders-service --shift-by -100`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeGroups,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reset := avro_kafka_config.OffsetReset{Strategy: avro_kafka_config.ResetShiftBy, Shift: shiftBy}
+			switch {
+			case toEarliest:
+				reset.Strategy = avro_kafka_config.ResetToEarliest
+			case toLatest:
+				reset.Strategy = avro_kafka_config.ResetToLatest
+			case toDatetime != "":
+				at, err := time.Parse(time.RFC3339, toDatetime)
+				if err != nil {
+					return fmt.Errorf("date invalide %q (format RFC 3339, ex : 2024-05-01T00:00:00Z)", toDatetime)
+				}
+				reset.Strategy, reset.Time = avro_kafka_config.ResetToDatetime, at
+			}
+			if err := a.load(); err != nil {
+				return err
+			}
+
+			changes, err := a.client.ResetOffsets(cmd.Context(), args[0], topics, reset, dryRun)
+			if err != nil {
+				return err
+			}
+			if err := a.render(cmd.OutOrStdout(), changes, func(w io.Writer) {
+				fmt.Fprintln(w, "TOPIC\tPARTITION\tACTUEL\tNOUVEL OFFSET")
+				for _, c := range changes {
+					fmt.Fprintf(w, "%s\t%d\t%s\t%d\n", c.Topic, c.Partition, formatOffset(c.Current), c.Target)
+				}
+			}); err != nil {
+				return err
+			}
+			if dryRun {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Simulation : aucun offset commité")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVar(&topics, "topic", nil, "topic à réinitialiser (répétable, défaut : tous les topics du groupe)")
+	cmd.Flags().BoolVar(&toEarliest, "to-earliest", false, "repart du premier message conservé")
+	cmd.Flags().BoolVar(&toLatest, "to-latest", false, "repart de la fin des partitions")
+	cmd.Flags().StringVar(&toDatetime, "to-datetime", "", "repart des messages publiés à partir de cette date (RFC 3339)")
+	cmd.Flags().Int64Var(&shiftBy, "shift-by", 0, "décale l'offset commité (négatif pour relire des messages)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "affiche les nouveaux offsets sans les commiter")
+	cmd.MarkFlagsOneRequired("to-earliest", "to-latest", "to-datetime", "shift-by")
+	cmd.MarkFlagsMutuallyExclusive("to-earliest", "to-latest", "to-datetime", "shift-by")
+	return cmd
+}
+
+// formatAssignments affiche les assignations sous la forme topic[0 1 2]
+func formatAssignments(assignments map[string][]int) string {
+	topics := make([]string, 0, len(assignments))
+	for topic := range assignments {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	var s string
+	for i, topic := range topics {
+		if i > 0 {
+			s += " "
+		}
+		s += fmt.Sprintf("%s%v", topic, assignments[topic])
+	}
+	return s
+}
+
+// formatOffset affiche « - » pour un offset inconnu
+func formatOffset(offset int64) string {
+	if offset < 0 {
+		return "-"
+	}
+	return strconv.FormatInt(offset, 10)
+}
+
+// completeGroups complète l'identifiant d'un groupe (premier argument uniquement)
+func (a *app) completeGroups(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 || a.load() != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	groups, err := a.client.ListGroups(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	ids := make([]string, 0, len(groups))
+	for _, g := range groups {
+		ids = append(ids, g.ID)
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_kafka_config"
+	"github.com/spf13/cobra"
+)
+
+// defaultManifestPath est le manifeste lu par plan et apply sans --file
+const defaultManifestPath = "topics.yaml"
+
+func newPlanCmd(a *app) *cobra.Command {
+	var path string
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Compare le manifeste de topics au cluster et affiche les changements",
+		Long: "Compare le manifeste YAML (topics, partitions, réplication, configurations, schéma) au cluster " +
+			"et au Schema Registry. Les changements « unsafe » (retrait de partitions, changement de réplication) " +
+			"ne sont jamais appliqués par apply.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			changes, err := a.plan(cmd, path)
+			if err != nil {
+				return err
+			}
+			return a.renderChanges(cmd.OutOrStdout(), changes)
+		},
+	}
+	cmd.Flags().StringVarP(&path, "file", "f", defaultManifestPath, "manifeste YAML des topics")
+	return cmd
+}
+
+func newApplyCmd(a *app) *cobra.Command {
+	var path string
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Applique le manifeste de topics (création, partitions, configurations, schémas)",
+		Long: "Calcule le plan puis applique les changements create et update. Rejouer apply sur un cluster " +
+			"à jour ne fait rien ; les changements unsafe sont signalés et font échouer la commande.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			changes, err := a.plan(cmd, path)
+			if err != nil {
+				return err
+			}
+			if err := a.renderChanges(cmd.OutOrStdout(), changes); err != nil {
+				return err
+			}
+			applied, err := a.client.Apply(cmd.Context(), changes)
+			if err != nil {
+				return fmt.Errorf("%d changement(s) appliqué(s) : %w", applied, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%d changement(s) appliqué(s)\n", applied)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&path, "file", "f", defaultManifestPath, "manifeste YAML des topics")
+	return cmd
+}
+
+// plan lit le manifeste et calcule les changements à appliquer
+func (a *app) plan(cmd *cobra.Command, path string) ([]avro_kafka_config.Change, error) {
+	manifest, err := avro_kafka_config.LoadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.load(); err != nil {
+		return nil, err
+	}
+	return a.client.Plan(cmd.Context(), manifest)
+}
+
+func (a *app) renderChanges(w io.Writer, changes []avro_kafka_config.Change) error {
+	if changes == nil {
+		changes = []avro_kafka_config.Change{}
+	}
+	return a.render(w, changes, func(w io.Writer) {
+		if len(changes) == 0 {
+			fmt.Fprintln(w, "Aucun changement : le cluster est conforme au manifeste")
+			return
+		}
+		fmt.Fprintln(w, "ACTION\tTOPIC\tÉLÉMENT\tDÉTAIL")
+		for _, c := range changes {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Action, c.Topic, c.Field, c.Detail)
+		}
+	})
+}
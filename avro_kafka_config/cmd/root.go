@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_kafka_config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats de sortie acceptés par --output
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// app regroupe les options globales et les clients partagés par les commandes
+type app struct {
+	configPath string
+	profile    string
+	output     string
+	strategy   string
+
+	cfg    avro_kafka_config.Config
+	client *avro_kafka_config.KafkaClient
+	loaded bool
+}
+
+func newRootCmd() *cobra.Command {
+	a := &app{}
+
+	root := &cobra.Command{
+		Use:           "avro_kafka_config",
+		Short:         "Gestion des topics, schémas Avro et dead letter queues sur Confluent Cloud",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			switch a.output {
+			case outputTable, outputJSON, outputYAML:
+				return nil
+			default:
+				return fmt.Errorf("format de sortie inconnu %q (table, json ou yaml)", a.output)
+			}
+		},
+	}
+	root.SetErrPrefix("Erreur :")
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.configPath, "config", avro_kafka_config.DefaultConfigPath, "fichier .env de connexion à Confluent Cloud")
+	flags.StringVar(&a.profile, "profile", "", "profil à charger (lit <config>.<profil>, ex : cmd/.env.prod)")
+	flags.StringVarP(&a.output, "output", "o", outputTable, "format de sortie : table, json ou yaml")
+	_ = root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(
+		[]string{outputTable, outputJSON, outputYAML}, cobra.ShellCompDirectiveNoFileComp))
+	flags.StringVar(&a.strategy, "subject-strategy", "", "nommage des sujets du Schema Registry : topic, record ou topic_record (défaut : CONFLUENT_SUBJECT_NAME_STRATEGY)")
+	_ = root.RegisterFlagCompletionFunc("subject-strategy", cobra.FixedCompletions(
+		[]string{"topic", "record", "topic_record"}, cobra.ShellCompDirectiveNoFileComp))
+
+	root.AddCommand(
+		newTopicsCmd(a),
+		newSchemasCmd(a),
+		newDLQCmd(a),
+		newGroupsCmd(a),
+		newACLsCmd(a),
+		newPlanCmd(a),
+		newApplyCmd(a),
+	)
+	addLegacyCommands(root, a)
+
+	return root
+}
+
+// load lit la configuration et crée le client Kafka (une seule fois) ;
+// appelée par les commandes qui en ont besoin, pour que --help et completion
+// fonctionnent sans fichier .env
+func (a *app) load() error {
+	if a.loaded {
+		return nil
+	}
+	cfg, err := avro_kafka_config.LoadConfigFrom(avro_kafka_config.ProfilePath(a.configPath, a.profile))
+	if err != nil {
+		return err
+	}
+	if a.strategy != "" {
+		cfg.SubjectNameStrategy = a.strategy
+	}
+	a.cfg = cfg
+	a.client = avro_kafka_config.NewKafkaClient(cfg)
+	a.loaded = true
+	return nil
+}
+
+// render écrit `data` au format demandé ; `table` produit la vue tabulaire
+func (a *app) render(w io.Writer, data any, table func(w io.Writer)) error {
+	switch a.output {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(data)
+	case outputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		defer enc.Close()
+		return enc.Encode(data)
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		table(tw)
+		return tw.Flush()
+	}
+}
+
+// addLegacyCommands conserve les anciennes commandes (list-topics, create-topic,
+// register-schema) comme alias masqués des nouvelles
+func addLegacyCommands(root *cobra.Command, a *app) {
+	legacy := []struct {
+		cmd         *cobra.Command
+		name, usage string
+	}{
+		{newTopicsListCmd(a), "list-topics", "topics list"},
+		{newTopicsCreateCmd(a), "create-topic", "topics create"},
+		{newSchemasRegisterCmd(a), "register-schema", "schemas register"},
+	}
+	for _, l := range legacy {
+		l.cmd.Use = l.name + strings.TrimPrefix(l.cmd.Use, l.cmd.Name())
+		l.cmd.Hidden = true
+		l.cmd.Deprecated = fmt.Sprintf("utiliser « %s »", l.usage)
+		root.AddCommand(l.cmd)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_kafka_config"
+	avroschemas "github.com/METAVENTUS/metaventus-kafka-adapters/avro_schemas"
+	"github.com/spf13/cobra"
+)
+
+func newSchemasCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schemas",
+		Short: "Gestion des schémas Avro (Schema Registry)",
+	}
+	cmd.AddCommand(
+		newSchemasListCmd(a),
+		newSchemasRegisterCmd(a),
+		newSchemasRegisterAllCmd(a),
+	)
+	return cmd
+}
+
+func newSchemasListCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Liste les schémas Avro connus (avroschemas.AvroSchemas)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			names := schemaNames()
+			return a.render(cmd.OutOrStdout(), names, func(w io.Writer) {
+				fmt.Fprintln(w, "SCHÉMA")
+				for _, name := range names {
+					fmt.Fprintln(w, name)
+				}
+			})
+		},
+	}
+}
+
+func newSchemasRegisterCmd(a *app) *cobra.Command {
+	var topic string
+	var key bool
+	cmd := &cobra.Command{
+		Use:   "register <schéma>",
+		Short: "Enregistre un schéma Avro dans le Schema Registry",
+		Long: "Enregistre le schéma sous le sujet calculé par --subject-strategy pour --topic : " +
+			"<topic>-value (ou <topic>-key avec --key), <namespace.record> ou <topic>-<namespace.record>. " +
+			"Sans --topic, le nom du schéma tient lieu de topic.",
+		Example: `  avro_kafka_config schemas register ModelExample --topic orders
+  avro_kafka_config schemas register OrderKey --topic orders --key
+  avro_kafka_config schemas register ModelExample --topic orders --subject-strategy topic_record`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSchemaNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.load(); err != nil {
+				return err
+			}
+			subject, err := avro_kafka_config.RegisterSchemaFor(a.cfg, topic, args[0], key)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Schéma %s enregistré avec succès (sujet %s)\n", args[0], subject)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&topic, "topic", "", "topic dont le schéma décrit les messages")
+	cmd.Flags().BoolVar(&key, "key", false, "schéma de clé (sujet <topic>-key) plutôt que de valeur")
+	return cmd
+}
+
+func newSchemasRegisterAllCmd(a *app) *cobra.Command {
+	var path string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "register-all",
+		Short: "Vérifie la compatibilité des schémas du manifeste puis les enregistre",
+		Long: "Teste les schémas de valeur (schema) et de clé (key_schema) des topics du manifeste contre la " +
+			"dernière version de leur sujet (<topic>-value, <topic>-key selon --subject-strategy) et affiche un " +
+			"rapport. Les schémas ne sont enregistrés que si tous passent ; la commande échoue sinon, ce qui " +
+			"permet de l'utiliser en CI avec --dry-run.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			manifest, err := avro_kafka_config.LoadManifest(path)
+			if err != nil {
+				return err
+			}
+			if err := a.load(); err != nil {
+				return err
+			}
+			results, err := avro_kafka_config.RegisterAll(a.cfg, manifest, dryRun)
+			if renderErr := a.render(cmd.OutOrStdout(), results, func(w io.Writer) {
+				fmt.Fprintln(w, "TOPIC\tSCHÉMA\tSUJET\tRÉSULTAT\tDÉTAIL")
+				for _, r := range results {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Topic, r.Schema, r.Subject, r.Status, strings.Join(r.Messages, " ; "))
+				}
+			}); renderErr != nil {
+				return renderErr
+			}
+			return err
+		},
+	}
+	cmd.Flags().StringVarP(&path, "file", "f", defaultManifestPath, "manifeste YAML des topics")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "vérifie la compatibilité sans enregistrer")
+	return cmd
+}
+
+// schemaNames retourne les noms triés des schémas de avroschemas.AvroSchemas
+func schemaNames() []string {
+	names := make([]string, 0, len(avroschemas.AvroSchemas))
+	for name := range avroschemas.AvroSchemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completeSchemaNames complète le nom d'un schéma (premier argument uniquement)
+func completeSchemaNames(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return schemaNames(), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_kafka_config"
+	"github.com/spf13/cobra"
+)
+
+func newTopicsCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "topics",
+		Short: "Gestion des topics Kafka",
+	}
+	cmd.AddCommand(
+		newTopicsListCmd(a),
+		newTopicsCreateCmd(a),
+		newTopicsCreateRetryCmd(a),
+		newTopicsDescribeCmd(a),
+		newTopicsDeleteCmd(a),
+		newTopicsAddPartitionsCmd(a),
+		newTopicsConfigCmd(a),
+	)
+	return cmd
+}
+
+func newTopicsListCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Liste les topics du cluster",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := a.load(); err != nil {
+				return err
+			}
+			topics, err := a.client.ListTopics(cmd.Context())
+			if err != nil {
+				return err
+			}
+			return a.render(cmd.OutOrStdout(), topics, func(w io.Writer) {
+				fmt.Fprintln(w, "TOPIC")
+				for _, topic := range topics {
+					fmt.Fprintln(w, topic)
+				}
+			})
+		},
+	}
+}
+
+// topicFlags regroupe les options communes aux commandes de création de topic
+type topicFlags struct {
+	partitions        int
+	replicationFactor int
+	configs           topicConfigFlag
+}
+
+func (f *topicFlags) register(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&f.partitions, "partitions", "p", 3, "nombre de partitions")
+	cmd.Flags().IntVarP(&f.replicationFactor, "replication-factor", "r", 0, "facteur de réplication (0 : valeur par défaut du broker)")
+	f.configs = topicConfigFlag{values: map[string]string{}}
+	cmd.Flags().Var(&f.configs, "topic-config", "configuration du topic (répétable, ex : retention.ms=86400000)")
+}
+
+func (f *topicFlags) spec(name string) avro_kafka_config.TopicSpec {
+	return avro_kafka_config.TopicSpec{
+		Name:              name,
+		Partitions:        f.partitions,
+		ReplicationFactor: f.replicationFactor,
+		Configs:           f.configs.values,
+	}
+}
+
+func newTopicsCreateCmd(a *app) *cobra.Command {
+	var flags topicFlags
+	cmd := &cobra.Command{
+		Use:   "create <topic>",
+		Short: "Crée un topic",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.load(); err != nil {
+				return err
+			}
+			created, err := a.client.CreateTopic(cmd.Context(), flags.spec(args[0]))
+			if err != nil {
+				return err
+			}
+			if !created {
+				fmt.Fprintf(cmd.OutOrStdout(), "Topic %s déjà existant (configuration inchangée)\n", args[0])
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Topic %s créé\n", args[0])
+			return nil
+		},
+	}
+	flags.register(cmd)
+	return cmd
+}
+
+func newTopicsCreateRetryCmd(a *app) *cobra.Command {
+	var flags topicFlags
+	var tiers []time.Duration
+	cmd := &cobra.Command{
+		Use:   "create-retry <topic>",
+		Short: "Crée les topics de retry <topic>.retry.<délai> lus par le Consumer",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.load(); err != nil {
+				return err
+			}
+			if err := a.client.CreateRetryTopics(cmd.Context(), flags.spec(args[0]), tiers); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Topics de retry de %s créés\n", args[0])
+			return nil
+		},
+	}
+	flags.register(cmd)
+	cmd.Flags().DurationSliceVar(&tiers, "tiers", []time.Duration{time.Minute, 10 * time.Minute, time.Hour}, "délais de retry")
+	return cmd
+}
+
+func newTopicsDescribeCmd(a *app) *cobra.Command {
+	var all bool
+	cmd := &cobra.Command{
+		Use:               "describe <topic>",
+		Short:             "Affiche les partitions (leader, réplicas, ISR) et la configuration d'un topic",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeTopics,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.load(); err != nil {
+				return err
+			}
+			desc, err := a.client.DescribeTopic(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			if !all {
+				desc.Configs = nonDefaultConfigs(desc.Configs)
+			}
+			return a.render(cmd.OutOrStdout(), desc, func(w io.Writer) {
+				fmt.Fprintf(w, "Topic\t%s\nPartitions\t%d\nRéplication\t%d\n\n", desc.Name, len(desc.Partitions), desc.ReplicationFactor())
+				fmt.Fprintln(w, "PARTITION\tLEADER\tRÉPLICAS\tISR")
+				for _, p := range desc.Partitions {
+					fmt.Fprintf(w, "%d\t%d\t%v\t%v\n", p.ID, p.Leader, p.Replicas, p.ISR)
+				}
+				fmt.Fprintln(w)
+				renderConfigs(w, desc.Configs)
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "affiche aussi les configurations par défaut")
+	return cmd
+}
+
+func newTopicsDeleteCmd(a *app) *cobra.Command {
+	var yes bool
+	cmd := &cobra.Command{
+		Use:               "delete <topic>",
+		Short:             "Supprime un topic et tous ses messages",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeTopics,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !yes {
+				return fmt.Errorf("la suppression de %s est irréversible : confirmer avec --yes", args[0])
+			}
+			if err := a.load(); err != nil {
+				return err
+			}
+			if err := a.client.DeleteTopic(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Topic %s supprimé\n", args[0])
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&yes, "yes", false, "confirme la suppression")
+	return cmd
+}
+
+func newTopicsAddPartitionsCmd(a *app) *cobra.Command {
+	var partitions int
+	cmd := &cobra.Command{
+		Use:               "add-partitions <topic>",
+		Short:             "Augmente le nombre de partitions d'un topic",
+		Long:              "Porte le nombre total de partitions du topic à --partitions. Kafka ne permet pas d'en retirer, et la répartition des clés existantes change.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeTopics,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.load(); err != nil {
+				return err
+			}
+			if err := a.client.CreatePartitions(cmd.Context(), args[0], partitions); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Topic %s : %d partitions\n", args[0], partitions)
+			return nil
+		},
+	}
+	cmd.Flags().IntVarP(&partitions, "partitions", "p", 0, "nouveau nombre total de partitions")
+	_ = cmd.MarkFlagRequired("partitions")
+	return cmd
+}
+
+func newTopicsConfigCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Lecture et modification de la configuration d'un topic",
+	}
+
+	get := &cobra.Command{
+		Use:               "get <topic> [clé...]",
+		Short:             "Affiche la configuration d'un topic (toutes les clés si aucune n'est précisée)",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: a.completeTopics,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.load(); err != nil {
+				return err
+			}
+			entries, err := a.client.DescribeTopicConfig(cmd.Context(), args[0], args[1:]...)
+			if err != nil {
+				return err
+			}
+			return a.render(cmd.OutOrStdout(), entries, func(w io.Writer) { renderConfigs(w, entries) })
+		},
+	}
+
+	var reset []string
+	set := &cobra.Command{
+		Use:               "set <topic> [clé=valeur...]",
+		Short:             "Modifie la configuration d'un topic (retention.ms, cleanup.policy, min.insync.replicas…)",
+		Example:           "  avro_kafka_config topics config set orders retention.ms=604800000 cleanup.policy=compact --reset min.insync.replicas",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: a.completeTopics,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			values := map[string]string{}
+			for _, arg := range args[1:] {
+				name, value, ok := strings.Cut(arg, "=")
+				if !ok || name == "" {
+					return fmt.Errorf("configuration invalide %q (attendu clé=valeur)", arg)
+				}
+				values[name] = value
+			}
+			if len(values) == 0 && len(reset) == 0 {
+				return fmt.Errorf("aucune configuration à modifier")
+			}
+			if err := a.load(); err != nil {
+				return err
+			}
+			if err := a.client.AlterTopicConfig(cmd.Context(), args[0], values, reset...); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Configuration de %s modifiée\n", args[0])
+			return nil
+		},
+	}
+	set.Flags().StringArrayVar(&reset, "reset", nil, "clé à remettre à sa valeur par défaut (répétable)")
+
+	cmd.AddCommand(get, set)
+	return cmd
+}
+
+// nonDefaultConfigs ne garde que les configurations modifiées
+func nonDefaultConfigs(entries []avro_kafka_config.ConfigEntry) []avro_kafka_config.ConfigEntry {
+	var kept []avro_kafka_config.ConfigEntry
+	for _, e := range entries {
+		if !e.Default {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+func renderConfigs(w io.Writer, entries []avro_kafka_config.ConfigEntry) {
+	fmt.Fprintln(w, "CONFIGURATION\tVALEUR\tDÉFAUT")
+	for _, e := range entries {
+		value := e.Value
+		if e.Sensitive {
+			value = "******"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\n", e.Name, value, e.Default)
+	}
+}
+
+// completeTopics complète le nom d'un topic (premier argument uniquement)
+func (a *app) completeTopics(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 || a.load() != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	topics, err := a.client.ListTopics(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return topics, cobra.ShellCompDirectiveNoFileComp
+}
+
+// topicConfigFlag collecte les options --topic-config clé=valeur d'une commande
+// (--config reste réservée au fichier .env)
+type topicConfigFlag struct {
+	values map[string]string
+}
+
+func (f *topicConfigFlag) String() string {
+	pairs := make([]string, 0, len(f.values))
+	for k, v := range f.values {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f *topicConfigFlag) Set(value string) error {
+	name, val, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("configuration invalide %q (attendu clé=valeur)", value)
+	}
+	f.values[name] = val
+	return nil
+}
+
+func (f *topicConfigFlag) Type() string { return "clé=valeur" }
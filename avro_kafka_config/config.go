@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -17,27 +18,75 @@ type Config struct {
 	SchemaRegistryURL    string
 	SchemaRegistryKey    string
 	SchemaRegistrySecret string
+	SubjectNameStrategy  string // topic (défaut), record ou topic_record
+	SecurityProtocol     string // PLAINTEXT, SSL, SASL_PLAINTEXT ou SASL_SSL ; vide : voir securityProtocol
 }
 
+// Protocoles de sécurité acceptés (noms Kafka)
+const (
+	ProtocolPlaintext     = "PLAINTEXT"
+	ProtocolSSL           = "SSL"
+	ProtocolSASLPlaintext = "SASL_PLAINTEXT"
+	ProtocolSASLSSL       = "SASL_SSL"
+)
+
+// securityProtocol retourne le protocole configuré ; à défaut SASL_SSL si une
+// clé d'API est renseignée (Confluent Cloud), PLAINTEXT sinon (broker local)
+func (c Config) securityProtocol() string {
+	switch {
+	case c.SecurityProtocol != "":
+		return strings.ToUpper(c.SecurityProtocol)
+	case c.APIKey != "":
+		return ProtocolSASLSSL
+	default:
+		return ProtocolPlaintext
+	}
+}
+
+// DefaultConfigPath est le fichier .env lu par défaut (relatif au dossier avro_kafka_config)
+var DefaultConfigPath = filepath.Join("cmd", ".env")
+
 // LoadConfig charge la configuration depuis le fichier .env dans `cmd/`
 func LoadConfig() Config {
-	// Définir le chemin vers `.env` dans `cmd/`
-	envPath := filepath.Join("cmd", ".env")
-
-	// Charger le fichier `.env`
-	err := godotenv.Load(envPath)
+	cfg, err := LoadConfigFrom(DefaultConfigPath)
 	if err != nil {
-		log.Fatalf("Erreur lors du chargement du fichier .env (%s) : %v", envPath, err)
+		log.Fatalf("%v", err)
 	}
+	return cfg
+}
+
+// ProfilePath retourne le fichier .env du profil `profile` (ex : cmd/.env.prod),
+// ou `path` si aucun profil n'est demandé
+func ProfilePath(path, profile string) string {
+	if profile == "" {
+		return path
+	}
+	return path + "." + profile
+}
 
-	return Config{
+// LoadConfigFrom charge la configuration depuis le fichier .env `path` ;
+// les variables déjà définies dans l'environnement sont prioritaires
+func LoadConfigFrom(path string) (Config, error) {
+	if err := godotenv.Load(path); err != nil {
+		return Config{}, fmt.Errorf("erreur lors du chargement du fichier .env (%s) : %w", path, err)
+	}
+
+	cfg := Config{
 		BootstrapServers:     os.Getenv("CONFLUENT_BOOTSTRAP_SERVERS"),
 		APIKey:               os.Getenv("CONFLUENT_API_KEY"),
 		APISecret:            os.Getenv("CONFLUENT_API_SECRET"),
 		SchemaRegistryURL:    os.Getenv("CONFLUENT_SCHEMA_REGISTRY_URL"),
 		SchemaRegistryKey:    os.Getenv("CONFLUENT_SCHEMA_REGISTRY_KEY"),
 		SchemaRegistrySecret: os.Getenv("CONFLUENT_SCHEMA_REGISTRY_SECRET"),
+		SubjectNameStrategy:  os.Getenv("CONFLUENT_SUBJECT_NAME_STRATEGY"),
+		SecurityProtocol:     os.Getenv("CONFLUENT_SECURITY_PROTOCOL"),
+	}
+	switch cfg.securityProtocol() {
+	case ProtocolPlaintext, ProtocolSSL, ProtocolSASLPlaintext, ProtocolSASLSSL:
+	default:
+		return Config{}, fmt.Errorf("CONFLUENT_SECURITY_PROTOCOL invalide %q (PLAINTEXT, SSL, SASL_PLAINTEXT ou SASL_SSL)", cfg.SecurityProtocol)
 	}
+	return cfg, nil
 }
 
 // Print affiche la configuration actuelle
@@ -0,0 +1,40 @@
+package avro_kafka_config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigFrom_Profile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(path+".staging", []byte("CONFLUENT_BOOTSTRAP_SERVERS=staging:9092\n"), 0o600))
+	t.Setenv("CONFLUENT_BOOTSTRAP_SERVERS", "")
+	os.Unsetenv("CONFLUENT_BOOTSTRAP_SERVERS")
+
+	cfg, err := LoadConfigFrom(ProfilePath(path, "staging"))
+	require.NoError(t, err)
+	assert.Equal(t, "staging:9092", cfg.BootstrapServers)
+
+	_, err = LoadConfigFrom(ProfilePath(path, ""))
+	assert.Error(t, err)
+}
+
+func TestLoadConfigFrom_SecurityProtocol(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("CONFLUENT_SECURITY_PROTOCOL=SASL_SSL\n"), 0o600))
+	t.Setenv("CONFLUENT_SECURITY_PROTOCOL", "")
+	os.Unsetenv("CONFLUENT_SECURITY_PROTOCOL")
+
+	cfg, err := LoadConfigFrom(path)
+	require.NoError(t, err)
+	assert.Equal(t, ProtocolSASLSSL, cfg.securityProtocol())
+
+	t.Setenv("CONFLUENT_SECURITY_PROTOCOL", "KERBEROS")
+	_, err = LoadConfigFrom(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,319 @@
+package avro_kafka_config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/consumer"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/registry"
+	"github.com/hamba/avro"
+	"github.com/segmentio/kafka-go"
+)
+
+// fetchTimeout borne l'attente d'un message : au-delà, la partition est
+// considérée comme lue (offsets finaux absents après compaction, marqueurs de
+// transaction…)
+const fetchTimeout = 10 * time.Second
+
+// ReadTopic appelle `fn` pour chaque message présent dans `topic` au moment de
+// l'appel (partition par partition, dans l'ordre des offsets) ; la lecture
+// s'arrête à la première erreur retournée par `fn`
+func (kc *KafkaClient) ReadTopic(ctx context.Context, topic string, fn func(kafka.Message) error) error {
+	conn, err := kc.dialer().DialContext(ctx, "tcp", kc.config.BootstrapServers)
+	if err != nil {
+		return fmt.Errorf("erreur de connexion à Kafka : %w", err)
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions(topic)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la lecture des partitions : %w", err)
+	}
+
+	for _, p := range partitions {
+		if err := kc.readPartition(ctx, topic, p.ID, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadMessage lit le message à `offset` dans la partition `partition` de `topic`
+func (kc *KafkaClient) ReadMessage(ctx context.Context, topic string, partition int, offset int64) (kafka.Message, error) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   []string{kc.config.BootstrapServers},
+		Topic:     topic,
+		Partition: partition,
+		Dialer:    kc.dialer(),
+	})
+	defer reader.Close()
+
+	if err := reader.SetOffset(offset); err != nil {
+		return kafka.Message{}, fmt.Errorf("erreur de positionnement sur l'offset %d : %w", offset, err)
+	}
+	msg, err := reader.FetchMessage(ctx)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("erreur de lecture de l'offset %d : %w", offset, err)
+	}
+	if msg.Offset != offset {
+		return kafka.Message{}, fmt.Errorf("offset %d introuvable dans %s/%d", offset, topic, partition)
+	}
+	return msg, nil
+}
+
+// readPartition lit une partition de son premier offset jusqu'au dernier offset
+// connu au moment de l'appel, ou jusqu'à fetchTimeout sans nouveau message
+func (kc *KafkaClient) readPartition(ctx context.Context, topic string, partition int, fn func(kafka.Message) error) error {
+	leader, err := kc.dialer().DialLeader(ctx, "tcp", kc.config.BootstrapServers, topic, partition)
+	if err != nil {
+		return fmt.Errorf("erreur de connexion au leader de %s/%d : %w", topic, partition, err)
+	}
+	first, last, err := leader.ReadOffsets()
+	leader.Close()
+	if err != nil {
+		return fmt.Errorf("erreur de lecture des offsets de %s/%d : %w", topic, partition, err)
+	}
+	if first >= last {
+		return nil
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   []string{kc.config.BootstrapServers},
+		Topic:     topic,
+		Partition: partition,
+		Dialer:    kc.dialer(),
+	})
+	defer reader.Close()
+
+	if err := reader.SetOffset(first); err != nil {
+		return fmt.Errorf("erreur de positionnement sur %s/%d : %w", topic, partition, err)
+	}
+
+	for reader.Offset() < last {
+		fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+		msg, err := reader.FetchMessage(fetchCtx)
+		cancel()
+		switch {
+		case err == nil:
+		case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+			kc.logger.Warn("fin de partition non atteinte, lecture interrompue", slog.String("topic", topic),
+				slog.Int("partition", partition), slog.Int64("offset", reader.Offset()), slog.Int64("last", last))
+			return nil
+		default:
+			return fmt.Errorf("erreur de lecture de %s/%d : %w", topic, partition, err)
+		}
+		if msg.Offset >= last {
+			return nil
+		}
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replay republie les messages de `from` sélectionnés par `filter` sur le topic
+// `to`, ou à défaut sur leur topic d'origine (header original_topic), en
+// conservant clé et headers. Les headers de retry sont retirés pour que le
+// message reparte de zéro. Retourne le nombre de messages republiés.
+func (kc *KafkaClient) Replay(ctx context.Context, from string, filter MessageFilter, to string) (int, error) {
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(kc.config.BootstrapServers),
+		Transport:    kc.transport(),
+		Balancer:     &kafka.LeastBytes{},
+		RequiredAcks: kafka.RequireAll,
+	}
+	defer writer.Close()
+
+	replayed := 0
+	err := kc.ReadTopic(ctx, from, func(msg kafka.Message) error {
+		if !filter(msg) {
+			return nil
+		}
+		topic := to
+		if topic == "" {
+			topic = header(msg, consumer.HeaderOriginalTopic)
+		}
+		if topic == "" {
+			return fmt.Errorf("topic d'origine inconnu pour l'offset %d/%d : utiliser --to", msg.Partition, msg.Offset)
+		}
+
+		headers := slices.DeleteFunc(slices.Clone(msg.Headers), func(h kafka.Header) bool {
+			return h.Key == consumer.HeaderRetryAttempt || h.Key == consumer.HeaderRetryDueAt
+		})
+
+		err := writer.WriteMessages(ctx, kafka.Message{Topic: topic, Key: msg.Key, Value: msg.Value, Headers: headers})
+		if err != nil {
+			return fmt.Errorf("erreur de republication de l'offset %d/%d : %w", msg.Partition, msg.Offset, err)
+		}
+		replayed++
+		kc.logger.Debug("message republié", slog.String("topic", topic),
+			slog.Int("partition", msg.Partition), slog.Int64("offset", msg.Offset))
+		return nil
+	})
+	if err != nil {
+		return replayed, err
+	}
+
+	kc.logger.Info("messages republiés", slog.Int("count", replayed))
+	return replayed, nil
+}
+
+// DecodeAvro décode `value` avec le schéma Avro `schema` en valeurs génériques
+// (maps, slices…), prêtes à être affichées en JSON
+func DecodeAvro(schema string, value []byte) (any, error) {
+	s, err := avro.Parse(schema)
+	if err != nil {
+		return nil, fmt.Errorf("schéma Avro invalide : %w", err)
+	}
+	var decoded any
+	if err := avro.Unmarshal(s, value, &decoded); err != nil {
+		return nil, fmt.Errorf("erreur de décodage Avro : %w", err)
+	}
+	return decoded, nil
+}
+
+// ErrUnknownSchema indique que le schéma d'une valeur Avro brute n'a pas été fourni
+var ErrUnknownSchema = errors.New("schéma de la valeur inconnu")
+
+// DecodeValue décode la valeur d'un message : au format Confluent, avec le schéma
+// d'écriture obtenu du Schema Registry par son identifiant ; sinon (identifiant
+// inconnu, ou valeur Avro brute dont les premiers octets ressemblent à un en-tête
+// Confluent), en Avro brut avec le schéma `schemaName` (voir ResolveSchema).
+// Retourne ErrUnknownSchema pour une valeur Avro brute sans `schemaName`.
+func DecodeValue(ctx context.Context, cfg Config, schemaName string, value []byte) (any, error) {
+	var wireErr error
+	if id, payload, err := registry.Decode(value); err == nil {
+		writer, err := cfg.registryClient().SchemaByID(ctx, id)
+		switch {
+		case err == nil:
+			var decoded any
+			if err := avro.Unmarshal(writer, payload, &decoded); err == nil {
+				return decoded, nil
+			}
+			wireErr = fmt.Errorf("erreur de décodage Avro (schéma %d) : %w", id, err)
+		case !errors.Is(err, registry.ErrSchemaNotFound):
+			return nil, err
+		}
+	}
+
+	if schemaName == "" {
+		if wireErr != nil {
+			return nil, wireErr
+		}
+		return nil, ErrUnknownSchema
+	}
+	schema, err := ResolveSchema(ctx, cfg, schemaName)
+	if err == nil {
+		var decoded any
+		if decoded, err = DecodeAvro(schema, value); err == nil {
+			return decoded, nil
+		}
+	}
+	if wireErr != nil {
+		return nil, fmt.Errorf("%w ; en Avro brut : %w", wireErr, err)
+	}
+	return nil, err
+}
+
+// MessageFilter sélectionne des messages (voir ParseMessageFilter)
+type MessageFilter func(kafka.Message) bool
+
+// ParseMessageFilter construit un filtre à partir d'expressions `champ<op>valeur`,
+// toutes devant être vérifiées. Les champs sont `key`, `partition`, `offset` ou
+// le nom d'un header (ex : `error`, `original_topic`) ; les opérateurs sont
+// `=`, `!=`, `~` (contient) et, pour partition et offset, `>=` et `<=`.
+func ParseMessageFilter(exprs []string) (MessageFilter, error) {
+	var filters []MessageFilter
+	for _, expr := range exprs {
+		f, err := parseFilterExpr(expr)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+
+	return func(msg kafka.Message) bool {
+		for _, f := range filters {
+			if !f(msg) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func parseFilterExpr(expr string) (MessageFilter, error) {
+	field, op, value := splitFilterExpr(expr)
+	if field == "" {
+		return nil, errors.New("filtre invalide : " + expr)
+	}
+
+	if field == "partition" || field == "offset" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("filtre %q : valeur numérique attendue", expr)
+		}
+		get := func(msg kafka.Message) int64 { return msg.Offset }
+		if field == "partition" {
+			get = func(msg kafka.Message) int64 { return int64(msg.Partition) }
+		}
+		switch op {
+		case "=":
+			return func(msg kafka.Message) bool { return get(msg) == n }, nil
+		case "!=":
+			return func(msg kafka.Message) bool { return get(msg) != n }, nil
+		case ">=":
+			return func(msg kafka.Message) bool { return get(msg) >= n }, nil
+		case "<=":
+			return func(msg kafka.Message) bool { return get(msg) <= n }, nil
+		}
+		return nil, fmt.Errorf("filtre %q : opérateur %s non supporté pour %s", expr, op, field)
+	}
+
+	get := func(msg kafka.Message) string { return header(msg, field) }
+	if field == "key" {
+		get = func(msg kafka.Message) string { return string(msg.Key) }
+	}
+	switch op {
+	case "=":
+		return func(msg kafka.Message) bool { return get(msg) == value }, nil
+	case "!=":
+		return func(msg kafka.Message) bool { return get(msg) != value }, nil
+	case "~":
+		return func(msg kafka.Message) bool { return strings.Contains(get(msg), value) }, nil
+	}
+	return nil, fmt.Errorf("filtre %q : opérateur %s non supporté pour %s", expr, op, field)
+}
+
+// splitFilterExpr découpe une expression sur son premier opérateur
+func splitFilterExpr(expr string) (field, op, value string) {
+	i := strings.IndexAny(expr, "!<>=~")
+	if i <= 0 {
+		return "", "", ""
+	}
+	op = expr[i : i+1]
+	if i+1 < len(expr) && expr[i+1] == '=' && op != "=" && op != "~" {
+		op += "="
+	}
+	if op == "!" || op == "<" || op == ">" {
+		return "", "", ""
+	}
+	return expr[:i], op, expr[i+len(op):]
+}
+
+// header retourne la valeur du header `name` (vide s'il est absent)
+func header(msg kafka.Message, name string) string {
+	for _, h := range msg.Headers {
+		if h.Key == name {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package avro_kafka_config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_schemas/schemas"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/registry"
+	"github.com/hamba/avro"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMessageFilter(t *testing.T) {
+	msg := kafka.Message{
+		Partition: 1,
+		Offset:    42,
+		Key:       []byte("user-1"),
+		Headers: []kafka.Header{
+			{Key: "original_topic", Value: []byte("orders")},
+			{Key: "error", Value: []byte("timeout: base indisponible")},
+		},
+	}
+
+	match := func(exprs ...string) bool {
+		filter, err := ParseMessageFilter(exprs)
+		require.NoError(t, err)
+		return filter(msg)
+	}
+
+	assert.True(t, match())
+	assert.True(t, match("original_topic=orders", "error~timeout"))
+	assert.True(t, match("offset>=40", "offset<=42", "partition=1"))
+	assert.True(t, match("key!=user-2"))
+	assert.False(t, match("offset>=43"))
+	assert.False(t, match("original_topic=orders", "key=user-2"))
+
+	for _, invalid := range []string{"offset", "=orders", "offset>=abc", "key>=a"} {
+		_, err := ParseMessageFilter([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestDecodeAvro(t *testing.T) {
+	event := models.ModelExample{ID: "1", Email: "a@b.c", Name: "Alice"}
+	value, err := avro.Marshal(avro.MustParse(schemas.ExampleSchema), event)
+	require.NoError(t, err)
+
+	decoded, err := DecodeAvro(schemas.ExampleSchema, value)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": "1", "email": "a@b.c", "name": "Alice"}, decoded)
+
+	_, err = DecodeAvro(schemas.ExampleSchema, []byte{0xff})
+	assert.Error(t, err)
+}
+
+func TestDecodeValue_WireFormat(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schemas/ids/7" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":40403}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(SchemaRequest{Schema: schemas.ExampleSchema})
+	}))
+	defer srv.Close()
+	cfg := Config{SchemaRegistryURL: srv.URL}
+
+	event := models.ModelExample{ID: "1", Email: "a@b.c", Name: "Alice"}
+	value, err := avro.Marshal(avro.MustParse(schemas.ExampleSchema), event)
+	require.NoError(t, err)
+	want := map[string]any{"id": "1", "email": "a@b.c", "name": "Alice"}
+
+	decoded, err := DecodeValue(context.Background(), cfg, "", registry.Encode(7, value))
+	require.NoError(t, err)
+	assert.Equal(t, want, decoded)
+
+	// Avro brut : schéma local
+	decoded, err = DecodeValue(context.Background(), cfg, schemas.ExampleName, value)
+	require.NoError(t, err)
+	assert.Equal(t, want, decoded)
+
+	// Avro brut sans schéma : le nom du topic n'en tient pas lieu
+	_, err = DecodeValue(context.Background(), cfg, "", value)
+	assert.ErrorIs(t, err, ErrUnknownSchema)
+
+	// le contexte de la commande est transmis au Schema Registry
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = DecodeValue(ctx, cfg, "", registry.Encode(7, value))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// Une valeur Avro brute commençant par un octet nul passe pour le format Confluent
+func TestDecodeValue_FallbackOnDecodeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(SchemaRequest{Schema: `{"type": "enum", "name": "E", "symbols": ["X"]}`})
+	}))
+	defer srv.Close()
+	cfg := Config{SchemaRegistryURL: srv.URL}
+
+	event := models.ModelExample{ID: "", Email: "a@b.c", Name: "Alice"}
+	value, err := avro.Marshal(avro.MustParse(schemas.ExampleSchema), event)
+	require.NoError(t, err)
+	_, _, err = registry.Decode(value)
+	require.NoError(t, err, "la valeur doit ressembler au format Confluent")
+
+	decoded, err := DecodeValue(context.Background(), cfg, schemas.ExampleName, value)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": "", "email": "a@b.c", "name": "Alice"}, decoded)
+
+	_, err = DecodeValue(context.Background(), cfg, schemas.ExampleName, []byte{0, 0x63, 0x63, 0x63, 0x63, 0x63})
+	assert.Error(t, err)
+}
@@ -0,0 +1,384 @@
+package avro_kafka_config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ErrGroupActive indique qu'un groupe a encore des membres : ses offsets ne peuvent pas être modifiés
+var ErrGroupActive = errors.New("le groupe de consommateurs a des membres actifs")
+
+// GroupSummary identifie un groupe de consommateurs
+type GroupSummary struct {
+	ID          string `json:"id" yaml:"id"`
+	Coordinator int    `json:"coordinator" yaml:"coordinator"`
+}
+
+// GroupDescription décrit un groupe de consommateurs : membres, assignations et lag
+type GroupDescription struct {
+	ID      string         `json:"id" yaml:"id"`
+	State   string         `json:"state" yaml:"state"`
+	Members []GroupMember  `json:"members" yaml:"members"`
+	Offsets []PartitionLag `json:"offsets" yaml:"offsets"`
+}
+
+// Lag retourne la somme des lags connus du groupe
+func (g GroupDescription) Lag() int64 {
+	var lag int64
+	for _, o := range g.Offsets {
+		if o.Lag > 0 {
+			lag += o.Lag
+		}
+	}
+	return lag
+}
+
+// GroupMember est un membre d'un groupe et les partitions qui lui sont assignées
+type GroupMember struct {
+	ID          string           `json:"id" yaml:"id"`
+	ClientID    string           `json:"client_id" yaml:"client_id"`
+	Host        string           `json:"host" yaml:"host"`
+	Assignments map[string][]int `json:"assignments" yaml:"assignments"`
+}
+
+// PartitionLag compare l'offset commité d'une partition à son offset de fin.
+// Committed et Lag valent -1 si le groupe n'a rien commité sur la partition.
+type PartitionLag struct {
+	Topic     string `json:"topic" yaml:"topic"`
+	Partition int    `json:"partition" yaml:"partition"`
+	Committed int64  `json:"committed" yaml:"committed"`
+	End       int64  `json:"end" yaml:"end"`
+	Lag       int64  `json:"lag" yaml:"lag"`
+	Member    string `json:"member,omitempty" yaml:"member,omitempty"`
+}
+
+// topicPartition identifie une partition dans les réponses des API d'administration
+type topicPartition struct {
+	topic     string
+	partition int
+}
+
+// ListGroups liste les groupes de consommateurs du cluster, triés par identifiant
+func (kc *KafkaClient) ListGroups(ctx context.Context) ([]GroupSummary, error) {
+	res, err := kc.client().ListGroups(ctx, &kafka.ListGroupsRequest{})
+	if err == nil {
+		err = res.Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture des groupes : %w", err)
+	}
+
+	groups := make([]GroupSummary, 0, len(res.Groups))
+	for _, g := range res.Groups {
+		groups = append(groups, GroupSummary{ID: g.GroupID, Coordinator: g.Coordinator})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+	return groups, nil
+}
+
+// DescribeGroup retourne l'état, les membres et le lag par partition d'un groupe
+func (kc *KafkaClient) DescribeGroup(ctx context.Context, groupID string) (GroupDescription, error) {
+	res, err := kc.client().DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: []string{groupID}})
+	if err != nil {
+		return GroupDescription{}, fmt.Errorf("erreur lors de la description du groupe %s : %w", groupID, err)
+	}
+	if len(res.Groups) == 0 {
+		return GroupDescription{}, fmt.Errorf("groupe %s introuvable", groupID)
+	}
+	g := res.Groups[0]
+	if g.Error != nil {
+		return GroupDescription{}, fmt.Errorf("erreur lors de la description du groupe %s : %w", groupID, g.Error)
+	}
+
+	desc := GroupDescription{ID: g.GroupID, State: g.GroupState}
+	owners := map[topicPartition]string{}
+	for _, m := range g.Members {
+		member := GroupMember{ID: m.MemberID, ClientID: m.ClientID, Host: m.ClientHost, Assignments: map[string][]int{}}
+		for _, t := range m.MemberAssignments.Topics {
+			member.Assignments[t.Topic] = t.Partitions
+			for _, p := range t.Partitions {
+				owners[topicPartition{t.Topic, p}] = m.MemberID
+			}
+		}
+		desc.Members = append(desc.Members, member)
+	}
+	sort.Slice(desc.Members, func(i, j int) bool { return desc.Members[i].ID < desc.Members[j].ID })
+
+	committed, err := kc.committedOffsets(ctx, groupID)
+	if err != nil {
+		return GroupDescription{}, err
+	}
+	partitions := map[string][]int{}
+	for tp := range owners {
+		if _, ok := committed[tp]; !ok {
+			committed[tp] = -1
+		}
+	}
+	for tp := range committed {
+		partitions[tp.topic] = append(partitions[tp.topic], tp.partition)
+	}
+	if len(partitions) == 0 {
+		return desc, nil
+	}
+
+	ends, err := kc.listOffsets(ctx, partitions, kafka.LastOffsetOf)
+	if err != nil {
+		return GroupDescription{}, err
+	}
+	for tp, offset := range committed {
+		lag := PartitionLag{Topic: tp.topic, Partition: tp.partition, Committed: offset, End: ends[tp], Lag: -1, Member: owners[tp]}
+		if offset >= 0 {
+			lag.Lag = max(lag.End-offset, 0)
+		}
+		desc.Offsets = append(desc.Offsets, lag)
+	}
+	sortLags(desc.Offsets)
+	return desc, nil
+}
+
+func sortLags(lags []PartitionLag) {
+	sort.Slice(lags, func(i, j int) bool {
+		if lags[i].Topic != lags[j].Topic {
+			return lags[i].Topic < lags[j].Topic
+		}
+		return lags[i].Partition < lags[j].Partition
+	})
+}
+
+// committedOffsets retourne les offsets commités par le groupe sur tous ses topics
+func (kc *KafkaClient) committedOffsets(ctx context.Context, groupID string) (map[topicPartition]int64, error) {
+	res, err := kc.client().OffsetFetch(ctx, &kafka.OffsetFetchRequest{GroupID: groupID})
+	if err == nil {
+		err = res.Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture des offsets du groupe %s : %w", groupID, err)
+	}
+
+	offsets := map[topicPartition]int64{}
+	for topic, partitions := range res.Topics {
+		for _, p := range partitions {
+			if p.Error != nil {
+				return nil, fmt.Errorf("erreur lors de la lecture des offsets du groupe %s (%s/%d) : %w", groupID, topic, p.Partition, p.Error)
+			}
+			offsets[topicPartition{topic, p.Partition}] = p.CommittedOffset
+		}
+	}
+	return offsets, nil
+}
+
+// listOffsets retourne l'offset demandé par `request` (début, fin ou date) de chaque partition
+func (kc *KafkaClient) listOffsets(ctx context.Context, partitions map[string][]int, request func(partition int) kafka.OffsetRequest) (map[topicPartition]int64, error) {
+	req := &kafka.ListOffsetsRequest{Topics: make(map[string][]kafka.OffsetRequest, len(partitions))}
+	for topic, ids := range partitions {
+		for _, id := range ids {
+			req.Topics[topic] = append(req.Topics[topic], request(id))
+		}
+	}
+
+	res, err := kc.client().ListOffsets(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture des offsets : %w", err)
+	}
+
+	offsets := map[topicPartition]int64{}
+	for topic, list := range res.Topics {
+		for _, p := range list {
+			if p.Error != nil {
+				return nil, fmt.Errorf("erreur lors de la lecture des offsets de %s/%d : %w", topic, p.Partition, p.Error)
+			}
+			offset := max(p.FirstOffset, p.LastOffset)
+			for o := range p.Offsets {
+				offset = o
+			}
+			offsets[topicPartition{topic, p.Partition}] = offset
+		}
+	}
+	return offsets, nil
+}
+
+// ResetStrategy désigne la cible d'une réinitialisation d'offsets
+type ResetStrategy string
+
+const (
+	ResetToEarliest ResetStrategy = "earliest" // premier message conservé
+	ResetToLatest   ResetStrategy = "latest"   // fin de la partition (ignore les messages en attente)
+	ResetToDatetime ResetStrategy = "datetime" // premier message publié à partir de OffsetReset.Time
+	ResetShiftBy    ResetStrategy = "shift"    // offset commité décalé de OffsetReset.Shift
+)
+
+// OffsetReset décrit une réinitialisation d'offsets
+type OffsetReset struct {
+	Strategy ResetStrategy
+	Time     time.Time // ResetToDatetime
+	Shift    int64     // ResetShiftBy, négatif pour relire des messages
+}
+
+// OffsetChange est le nouvel offset d'une partition
+type OffsetChange struct {
+	Topic     string `json:"topic" yaml:"topic"`
+	Partition int    `json:"partition" yaml:"partition"`
+	Current   int64  `json:"current" yaml:"current"`
+	Target    int64  `json:"target" yaml:"target"`
+}
+
+// ResetOffsets réinitialise les offsets du groupe sur `topics` (par défaut, les
+// topics sur lesquels le groupe a commité) et retourne les changements. Le groupe
+// doit être vide (consommateurs arrêtés) ; avec `dryRun`, rien n'est commité.
+// Les cibles sont bornées au premier et au dernier offset de chaque partition ;
+// ResetShiftBy ignore les partitions sans offset commité.
+func (kc *KafkaClient) ResetOffsets(ctx context.Context, groupID string, topics []string, reset OffsetReset, dryRun bool) ([]OffsetChange, error) {
+	desc, err := kc.DescribeGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if len(desc.Members) > 0 && !dryRun {
+		return nil, fmt.Errorf("%w : %s (%d membre(s), état %s)", ErrGroupActive, groupID, len(desc.Members), desc.State)
+	}
+
+	committed := map[topicPartition]int64{}
+	for _, o := range desc.Offsets {
+		committed[topicPartition{o.Topic, o.Partition}] = o.Committed
+	}
+	if len(topics) == 0 {
+		seen := map[string]bool{}
+		for _, o := range desc.Offsets {
+			if !seen[o.Topic] {
+				seen[o.Topic] = true
+				topics = append(topics, o.Topic)
+			}
+		}
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("aucun topic à réinitialiser pour le groupe %s", groupID)
+	}
+
+	partitions, err := kc.partitions(ctx, topics)
+	if err != nil {
+		return nil, err
+	}
+	first, err := kc.listOffsets(ctx, partitions, kafka.FirstOffsetOf)
+	if err != nil {
+		return nil, err
+	}
+	last, err := kc.listOffsets(ctx, partitions, kafka.LastOffsetOf)
+	if err != nil {
+		return nil, err
+	}
+	var at map[topicPartition]int64
+	if reset.Strategy == ResetToDatetime {
+		at, err = kc.listOffsets(ctx, partitions, func(p int) kafka.OffsetRequest { return kafka.TimeOffsetOf(p, reset.Time) })
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch reset.Strategy {
+	case ResetToEarliest, ResetToLatest, ResetToDatetime, ResetShiftBy:
+	default:
+		return nil, fmt.Errorf("stratégie de réinitialisation inconnue %q", reset.Strategy)
+	}
+
+	var changes []OffsetChange
+	for topic, ids := range partitions {
+		for _, id := range ids {
+			tp := topicPartition{topic, id}
+			current, ok := committed[tp]
+			if !ok {
+				current = -1
+			}
+
+			target, ok := reset.target(current, first[tp], last[tp], at[tp])
+			if !ok {
+				continue
+			}
+			changes = append(changes, OffsetChange{Topic: topic, Partition: id, Current: current, Target: target})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Topic != changes[j].Topic {
+			return changes[i].Topic < changes[j].Topic
+		}
+		return changes[i].Partition < changes[j].Partition
+	})
+
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+	return changes, kc.commitOffsets(ctx, groupID, changes)
+}
+
+// target calcule le nouvel offset d'une partition, borné à [first, last] ;
+// false si la partition doit être ignorée
+func (r OffsetReset) target(current, first, last, at int64) (int64, bool) {
+	var target int64
+	switch r.Strategy {
+	case ResetToEarliest:
+		target = first
+	case ResetToLatest:
+		target = last
+	case ResetToDatetime:
+		target = at
+		if target < 0 { // aucun message après cette date
+			target = last
+		}
+	case ResetShiftBy:
+		if current < 0 {
+			return 0, false
+		}
+		target = current + r.Shift
+	default:
+		return 0, false
+	}
+	return min(max(target, first), last), true
+}
+
+// partitions retourne les identifiants de partitions de chaque topic
+func (kc *KafkaClient) partitions(ctx context.Context, topics []string) (map[string][]int, error) {
+	res, err := kc.client().Metadata(ctx, &kafka.MetadataRequest{Topics: topics})
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture des métadonnées : %w", err)
+	}
+
+	partitions := make(map[string][]int, len(res.Topics))
+	for _, t := range res.Topics {
+		if t.Error != nil {
+			return nil, fmt.Errorf("topic %s : %w", t.Name, t.Error)
+		}
+		for _, p := range t.Partitions {
+			partitions[t.Name] = append(partitions[t.Name], p.ID)
+		}
+	}
+	return partitions, nil
+}
+
+// commitOffsets commite les offsets cibles hors de toute génération du groupe
+func (kc *KafkaClient) commitOffsets(ctx context.Context, groupID string, changes []OffsetChange) error {
+	req := &kafka.OffsetCommitRequest{GroupID: groupID, GenerationID: -1, Topics: map[string][]kafka.OffsetCommit{}}
+	for _, c := range changes {
+		req.Topics[c.Topic] = append(req.Topics[c.Topic], kafka.OffsetCommit{Partition: c.Partition, Offset: c.Target})
+	}
+
+	res, err := kc.client().OffsetCommit(ctx, req)
+	if err == nil {
+		for _, partitions := range res.Topics {
+			for _, p := range partitions {
+				if p.Error != nil {
+					err = p.Error
+				}
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("erreur lors du commit des offsets du groupe %s : %w", groupID, err)
+	}
+
+	kc.logger.Info("offsets du groupe réinitialisés", slog.String("group_id", groupID), slog.Int("partitions", len(changes)))
+	return nil
+}
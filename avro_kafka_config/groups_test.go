@@ -0,0 +1,37 @@
+package avro_kafka_config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffsetReset_Target(t *testing.T) {
+	tests := []struct {
+		name                 string
+		reset                OffsetReset
+		current, first, last int64
+		at                   int64
+		want                 int64
+		ok                   bool
+	}{
+		{"earliest", OffsetReset{Strategy: ResetToEarliest}, 50, 10, 100, 0, 10, true},
+		{"latest", OffsetReset{Strategy: ResetToLatest}, 50, 10, 100, 0, 100, true},
+		{"datetime", OffsetReset{Strategy: ResetToDatetime}, 50, 10, 100, 42, 42, true},
+		{"datetime après le dernier message", OffsetReset{Strategy: ResetToDatetime}, 50, 10, 100, -1, 100, true},
+		{"shift arrière", OffsetReset{Strategy: ResetShiftBy, Shift: -20}, 50, 10, 100, 0, 30, true},
+		{"shift borné au début", OffsetReset{Strategy: ResetShiftBy, Shift: -100}, 50, 10, 100, 0, 10, true},
+		{"shift borné à la fin", OffsetReset{Strategy: ResetShiftBy, Shift: 100}, 50, 10, 100, 0, 100, true},
+		{"shift sans offset commité", OffsetReset{Strategy: ResetShiftBy, Shift: 1}, -1, 10, 100, 0, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := tt.reset.target(tt.current, tt.first, tt.last, tt.at)
+		assert.Equal(t, tt.ok, ok, tt.name)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func TestGroupDescription_Lag(t *testing.T) {
+	desc := GroupDescription{Offsets: []PartitionLag{{Lag: 3}, {Lag: -1}, {Lag: 7}}}
+	assert.Equal(t, int64(10), desc.Lag())
+}
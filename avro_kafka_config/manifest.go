@@ -0,0 +1,312 @@
+package avro_kafka_config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+
+	avroschemas "github.com/METAVENTUS/metaventus-kafka-adapters/avro_schemas"
+	"github.com/hamba/avro"
+	"github.com/segmentio/kafka-go"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestVersion est la version du format de manifeste supportée
+const ManifestVersion = 1
+
+// Manifest décrit de façon déclarative les topics d'un cluster (fichier YAML versionné)
+type Manifest struct {
+	Version int             `yaml:"version"`
+	Topics  []TopicManifest `yaml:"topics"`
+}
+
+// TopicManifest décrit un topic attendu. Les valeurs nulles ne sont pas gérées :
+// seules les configurations listées sont comparées au cluster.
+type TopicManifest struct {
+	Name              string            `yaml:"name"`
+	Partitions        int               `yaml:"partitions,omitempty"`
+	ReplicationFactor int               `yaml:"replication,omitempty"`
+	Configs           map[string]string `yaml:"configs,omitempty"`
+	Schema            string            `yaml:"schema,omitempty"`     // schéma de valeur (avroschemas.AvroSchemas), sujet selon SubjectNameStrategy
+	KeySchema         string            `yaml:"key_schema,omitempty"` // schéma de clé (avroschemas.AvroSchemas)
+	Producers         []string          `yaml:"producers,omitempty"`  // principaux autorisés à produire (ex : User:sa-12345)
+	Consumers         []ConsumerGrant   `yaml:"consumers,omitempty"`  // principaux autorisés à consommer
+}
+
+// ConsumerGrant autorise un principal à consommer un topic, au sein d'un groupe
+type ConsumerGrant struct {
+	Principal string `yaml:"principal"`
+	Group     string `yaml:"group,omitempty"`
+}
+
+// LoadManifest lit et valide un manifeste YAML
+func LoadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("impossible de lire le manifeste %s : %w", path, err)
+	}
+	m, err := ParseManifest(data)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("manifeste %s : %w", path, err)
+	}
+	return m, nil
+}
+
+// ParseManifest décode et valide un manifeste YAML ; les champs inconnus sont refusés
+func ParseManifest(data []byte) (Manifest, error) {
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return Manifest{}, fmt.Errorf("YAML invalide : %w", err)
+	}
+	return m, m.Validate()
+}
+
+// Validate vérifie la version du manifeste et l'unicité des topics
+func (m Manifest) Validate() error {
+	if m.Version != ManifestVersion {
+		return fmt.Errorf("version de manifeste %d non supportée (attendu %d)", m.Version, ManifestVersion)
+	}
+	seen := make(map[string]bool, len(m.Topics))
+	for i, t := range m.Topics {
+		switch {
+		case t.Name == "":
+			return fmt.Errorf("topic n°%d sans nom", i+1)
+		case seen[t.Name]:
+			return fmt.Errorf("topic %s déclaré plusieurs fois", t.Name)
+		case t.Partitions < 0 || t.ReplicationFactor < 0:
+			return fmt.Errorf("topic %s : partitions et réplication doivent être positives", t.Name)
+		case t.Schema != "" && avroschemas.AvroSchemas[t.Schema] == "":
+			return fmt.Errorf("topic %s : schéma %s introuvable dans avroschemas.AvroSchemas", t.Name, t.Schema)
+		case t.KeySchema != "" && avroschemas.AvroSchemas[t.KeySchema] == "":
+			return fmt.Errorf("topic %s : schéma de clé %s introuvable dans avroschemas.AvroSchemas", t.Name, t.KeySchema)
+		}
+		for _, p := range t.Producers {
+			if p == "" {
+				return fmt.Errorf("topic %s : producteur sans principal", t.Name)
+			}
+		}
+		for _, c := range t.Consumers {
+			if c.Principal == "" {
+				return fmt.Errorf("topic %s : consommateur sans principal", t.Name)
+			}
+		}
+		seen[t.Name] = true
+	}
+	return nil
+}
+
+// ChangeAction est la nature d'un changement du plan
+type ChangeAction string
+
+const (
+	ActionCreate ChangeAction = "create" // topic, ACL ou sujet à créer
+	ActionUpdate ChangeAction = "update" // modification applicable sans perte
+	ActionUnsafe ChangeAction = "unsafe" // modification impossible à appliquer automatiquement
+)
+
+// Change est un écart entre le manifeste et le cluster
+type Change struct {
+	Action ChangeAction `json:"action" yaml:"action"`
+	Topic  string       `json:"topic" yaml:"topic"`
+	Field  string       `json:"field" yaml:"field"` // topic, partitions, replication, config, acl ou schema
+	Detail string       `json:"detail" yaml:"detail"`
+
+	apply func(ctx context.Context, kc *KafkaClient) error
+}
+
+// Plan retourne les changements nécessaires pour aligner le cluster (topics, ACL)
+// et le Schema Registry sur le manifeste ; un plan vide signifie qu'ils sont à jour
+func (kc *KafkaClient) Plan(ctx context.Context, m Manifest) ([]Change, error) {
+	res, err := kc.client().Metadata(ctx, &kafka.MetadataRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture des métadonnées : %w", err)
+	}
+	live := make(map[string]kafka.Topic, len(res.Topics))
+	for _, t := range res.Topics {
+		live[t.Name] = t
+	}
+
+	var changes []Change
+	for _, want := range m.Topics {
+		topicChanges, err := kc.planTopic(ctx, want, live)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, topicChanges...)
+
+		aclChanges, err := kc.planACLs(ctx, want)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, aclChanges...)
+
+		schemaChanges, err := kc.planSchemas(want)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, schemaChanges...)
+	}
+	return changes, nil
+}
+
+func (kc *KafkaClient) planTopic(ctx context.Context, want TopicManifest, live map[string]kafka.Topic) ([]Change, error) {
+	t, exists := live[want.Name]
+	if !exists {
+		spec := TopicSpec{Name: want.Name, Partitions: want.Partitions, ReplicationFactor: want.ReplicationFactor, Configs: want.Configs}
+		return []Change{{
+			Action: ActionCreate, Topic: want.Name, Field: "topic",
+			Detail: fmt.Sprintf("partitions=%d réplication=%d configs=%d", want.Partitions, want.ReplicationFactor, len(want.Configs)),
+			apply: func(ctx context.Context, kc *KafkaClient) error {
+				_, err := kc.CreateTopic(ctx, spec)
+				return err
+			},
+		}}, nil
+	}
+
+	var changes []Change
+	if current := len(t.Partitions); want.Partitions > current {
+		count := want.Partitions
+		changes = append(changes, Change{
+			Action: ActionUpdate, Topic: want.Name, Field: "partitions",
+			Detail: fmt.Sprintf("%d → %d", current, count),
+			apply:  func(ctx context.Context, kc *KafkaClient) error { return kc.CreatePartitions(ctx, want.Name, count) },
+		})
+	} else if want.Partitions > 0 && want.Partitions < current {
+		changes = append(changes, Change{
+			Action: ActionUnsafe, Topic: want.Name, Field: "partitions",
+			Detail: fmt.Sprintf("%d → %d : Kafka ne permet pas de retirer des partitions", current, want.Partitions),
+		})
+	}
+
+	if len(t.Partitions) > 0 {
+		if current := len(t.Partitions[0].Replicas); want.ReplicationFactor > 0 && want.ReplicationFactor != current {
+			changes = append(changes, Change{
+				Action: ActionUnsafe, Topic: want.Name, Field: "replication",
+				Detail: fmt.Sprintf("%d → %d : nécessite une réassignation des partitions", current, want.ReplicationFactor),
+			})
+		}
+	}
+
+	if len(want.Configs) == 0 {
+		return changes, nil
+	}
+	names := make([]string, 0, len(want.Configs))
+	for name := range want.Configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries, err := kc.DescribeTopicConfig(ctx, want.Name, names...)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]string, len(entries))
+	for _, e := range entries {
+		current[e.Name] = e.Value
+	}
+	for _, name := range names {
+		value, was := want.Configs[name], current[name]
+		if value == was {
+			continue
+		}
+		changes = append(changes, Change{
+			Action: ActionUpdate, Topic: want.Name, Field: "config",
+			Detail: fmt.Sprintf("%s : %q → %q", name, was, value),
+			apply: func(ctx context.Context, kc *KafkaClient) error {
+				return kc.AlterTopicConfig(ctx, want.Name, map[string]string{name: value})
+			},
+		})
+	}
+	return changes, nil
+}
+
+// planSchemas planifie l'enregistrement des schémas de valeur et de clé du topic
+func (kc *KafkaClient) planSchemas(want TopicManifest) ([]Change, error) {
+	var changes []Change
+	for i, name := range [2]string{want.Schema, want.KeySchema} { // valeur puis clé
+		change, err := kc.planSchema(want.Name, name, i == 1)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+	return changes, nil
+}
+
+// planSchema compare le schéma local de clé ou de valeur de `topic` à la dernière
+// version enregistrée sous son sujet (empreintes des formes canoniques, le Schema
+// Registry reformatant les schémas)
+func (kc *KafkaClient) planSchema(topic, schemaName string, key bool) (*Change, error) {
+	if schemaName == "" {
+		return nil, nil
+	}
+	local, err := avro.Parse(avroschemas.AvroSchemas[schemaName])
+	if err != nil {
+		return nil, fmt.Errorf("schéma %s invalide : %w", schemaName, err)
+	}
+	subject, err := SchemaSubject(kc.config, topic, schemaName, key)
+	if err != nil {
+		return nil, err
+	}
+
+	field := "schema"
+	if key {
+		field = "key_schema"
+	}
+	change := &Change{
+		Topic: topic, Field: field,
+		apply: func(_ context.Context, kc *KafkaClient) error {
+			_, err := RegisterSchemaFor(kc.config, topic, schemaName, key)
+			return err
+		},
+	}
+	registered, err := GetLatestSchema(kc.config, subject)
+	switch {
+	case errors.Is(err, ErrSubjectNotFound):
+		change.Action, change.Detail = ActionCreate, subject
+		return change, nil
+	case err != nil:
+		return nil, err
+	}
+
+	remote, err := avro.Parse(registered)
+	if err != nil {
+		return nil, fmt.Errorf("schéma enregistré pour %s invalide : %w", subject, err)
+	}
+	if local.Fingerprint() == remote.Fingerprint() {
+		return nil, nil
+	}
+	change.Action, change.Detail = ActionUpdate, subject+" : nouvelle version"
+	return change, nil
+}
+
+// Apply exécute les changements du plan, dans l'ordre. Les changements `unsafe`
+// ne sont jamais appliqués : ils sont signalés par une erreur une fois les
+// autres appliqués. Rejouer Apply sur un cluster à jour ne fait rien.
+func (kc *KafkaClient) Apply(ctx context.Context, changes []Change) (applied int, err error) {
+	var unsafe int
+	for _, c := range changes {
+		if c.Action == ActionUnsafe || c.apply == nil {
+			unsafe++
+			kc.logger.Warn("changement non appliqué", slog.String("topic", c.Topic),
+				slog.String("field", c.Field), slog.String("detail", c.Detail))
+			continue
+		}
+		if err := c.apply(ctx, kc); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+	if unsafe > 0 {
+		return applied, fmt.Errorf("%d changement(s) non applicable(s) automatiquement", unsafe)
+	}
+	return applied, nil
+}
@@ -0,0 +1,111 @@
+package avro_kafka_config
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_schemas/schemas"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManifest(t *testing.T) {
+	m, err := ParseManifest([]byte(`
+version: 1
+topics:
+  - name: orders
+    partitions: 6
+    replication: 3
+    configs:
+      retention.ms: 604800000
+      cleanup.policy: compact
+    schema: ` + schemas.ExampleName + `
+    producers: [User:sa-1]
+    consumers:
+      - principal: User:sa-2
+        group: orders-service
+`))
+	require.NoError(t, err)
+	require.Len(t, m.Topics, 1)
+	assert.Equal(t, TopicManifest{
+		Name: "orders", Partitions: 6, ReplicationFactor: 3, Schema: schemas.ExampleName,
+		Configs:   map[string]string{"retention.ms": "604800000", "cleanup.policy": "compact"},
+		Producers: []string{"User:sa-1"},
+		Consumers: []ConsumerGrant{{Principal: "User:sa-2", Group: "orders-service"}},
+	}, m.Topics[0])
+}
+
+func TestParseManifest_Invalid(t *testing.T) {
+	for name, doc := range map[string]string{
+		"version":       "version: 2\ntopics: []",
+		"champ inconnu": "version: 1\ntopics:\n  - name: a\n    partition: 3",
+		"sans nom":      "version: 1\ntopics:\n  - partitions: 3",
+		"doublon":       "version: 1\ntopics:\n  - name: a\n  - name: a",
+		"schéma":        "version: 1\ntopics:\n  - name: a\n    schema: Inconnu",
+		"consommateur":  "version: 1\ntopics:\n  - name: a\n    consumers:\n      - group: g",
+	} {
+		_, err := ParseManifest([]byte(doc))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestPlanTopic(t *testing.T) {
+	kc := NewKafkaClient(Config{})
+	live := map[string]kafka.Topic{
+		"orders": {Name: "orders", Partitions: []kafka.Partition{
+			{ID: 0, Replicas: []kafka.Broker{{ID: 1}, {ID: 2}, {ID: 3}}},
+			{ID: 1, Replicas: []kafka.Broker{{ID: 1}, {ID: 2}, {ID: 3}}},
+		}},
+	}
+
+	changes, err := kc.planTopic(context.Background(), TopicManifest{Name: "payments", Partitions: 3}, live)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, ActionCreate, changes[0].Action)
+
+	changes, err = kc.planTopic(context.Background(), TopicManifest{Name: "orders", Partitions: 2, ReplicationFactor: 3}, live)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = kc.planTopic(context.Background(), TopicManifest{Name: "orders", Partitions: 4}, live)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, ActionUpdate, changes[0].Action)
+	assert.Equal(t, "partitions", changes[0].Field)
+
+	changes, err = kc.planTopic(context.Background(), TopicManifest{Name: "orders", Partitions: 1, ReplicationFactor: 1}, live)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, ActionUnsafe, changes[0].Action)
+	assert.Equal(t, ActionUnsafe, changes[1].Action)
+}
+
+func TestApply_SkipsUnsafe(t *testing.T) {
+	kc := NewKafkaClient(Config{})
+	var calls int
+	ok := func(context.Context, *KafkaClient) error { calls++; return nil }
+
+	applied, err := kc.Apply(context.Background(), []Change{
+		{Action: ActionCreate, apply: ok},
+		{Action: ActionUnsafe},
+		{Action: ActionUpdate, apply: ok},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 2, applied)
+	assert.Equal(t, 2, calls)
+
+	boom := errors.New("boom")
+	applied, err = kc.Apply(context.Background(), []Change{
+		{Action: ActionCreate, apply: func(context.Context, *KafkaClient) error { return boom }},
+		{Action: ActionUpdate, apply: ok},
+	})
+	assert.ErrorIs(t, err, boom)
+	assert.Equal(t, 0, applied)
+}
+
+func TestLoadManifest_Example(t *testing.T) {
+	_, err := LoadManifest("topics.example.yaml")
+	assert.NoError(t, err)
+}
@@ -1,53 +1,196 @@
 package avro_kafka_config
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	avroschemas "github.com/METAVENTUS/metaventus-kafka-adapters/avro_schemas"
-	"io/ioutil"
 	"net/http"
+
+	avroschemas "github.com/METAVENTUS/metaventus-kafka-adapters/avro_schemas"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/registry"
+	"github.com/hamba/avro"
 )
 
+// ErrSubjectNotFound indique qu'aucun schéma n'est enregistré pour un sujet
+var ErrSubjectNotFound = registry.ErrSubjectNotFound
+
+// ErrIncompatibleSchema indique qu'au moins un schéma est invalide ou incompatible
+// avec la dernière version enregistrée
+var ErrIncompatibleSchema = errors.New("schéma incompatible avec le Schema Registry")
+
 // SchemaRequest représente le format d'une requête pour ajouter un schéma Avro
 type SchemaRequest struct {
 	Schema string `json:"schema"`
 }
 
-// RegisterSchema enregistre un schéma Avro dans Confluent Schema Registry
-func RegisterSchema(cfg Config, schemaName string) error {
+// registryClient retourne un client du Schema Registry configuré par `cfg`
+func (c Config) registryClient() *registry.Client {
+	return registry.NewClient(registry.Config{
+		URL:      c.SchemaRegistryURL,
+		Username: c.SchemaRegistryKey,
+		Password: c.SchemaRegistrySecret,
+	})
+}
+
+// SchemaSubject retourne le sujet du schéma `schemaName` pour `topic` selon
+// Config.SubjectNameStrategy ; sans topic, le nom du schéma en tient lieu
+// (sujet <schemaName>-value avec la stratégie par défaut)
+func SchemaSubject(cfg Config, topic, schemaName string, key bool) (string, error) {
 	schema, exists := avroschemas.AvroSchemas[schemaName]
 	if !exists {
-		return fmt.Errorf("schéma introuvable pour %s", schemaName)
+		return "", fmt.Errorf("schéma introuvable pour %s", schemaName)
+	}
+	strategy, err := registry.ParseStrategy(cfg.SubjectNameStrategy)
+	if err != nil {
+		return "", err
+	}
+	if topic == "" {
+		topic = schemaName
 	}
+	return registry.Subject(strategy, topic, schema, key)
+}
 
-	url := fmt.Sprintf("%s/subjects/%s-value/versions", cfg.SchemaRegistryURL, schemaName)
+// RegisterSchema enregistre un schéma Avro dans Confluent Schema Registry
+func RegisterSchema(cfg Config, schemaName string) error {
+	_, err := RegisterSchemaFor(cfg, "", schemaName, false)
+	return err
+}
 
-	data, err := json.Marshal(SchemaRequest{Schema: schema})
+// RegisterSchemaFor enregistre le schéma `schemaName`, comme schéma de clé ou de
+// valeur de `topic`, sous le sujet calculé par SchemaSubject, et retourne ce sujet
+func RegisterSchemaFor(cfg Config, topic, schemaName string, key bool) (string, error) {
+	subject, err := SchemaSubject(cfg, topic, schemaName, key)
 	if err != nil {
-		return fmt.Errorf("erreur de conversion JSON : %w", err)
+		return "", err
+	}
+	if _, err := cfg.registryClient().Register(context.Background(), subject, avroschemas.AvroSchemas[schemaName]); err != nil {
+		return "", err
 	}
+	return subject, nil
+}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
-	if err != nil {
-		return fmt.Errorf("erreur lors de la création de la requête : %w", err)
+// GetLatestSchema retourne la dernière version du schéma enregistrée pour `subject`
+func GetLatestSchema(cfg Config, subject string) (string, error) {
+	return cfg.registryClient().Latest(context.Background(), subject)
+}
+
+// ResolveSchema retourne le schéma `schemaName` depuis avroschemas.AvroSchemas,
+// ou à défaut depuis le Schema Registry (sujet `<schemaName>-value`)
+func ResolveSchema(ctx context.Context, cfg Config, schemaName string) (string, error) {
+	if schema, exists := avroschemas.AvroSchemas[schemaName]; exists {
+		return schema, nil
 	}
+	return cfg.registryClient().Latest(ctx, registry.TopicNameStrategy(schemaName, "", false))
+}
+
+// Statuts d'un schéma après vérification de compatibilité
+const (
+	SchemaNew          = "nouveau"      // sujet absent du Schema Registry
+	SchemaCompatible   = "compatible"   // compatible avec la dernière version
+	SchemaIncompatible = "incompatible" // refusé par la règle de compatibilité du sujet
+	SchemaInvalid      = "invalide"     // schéma local illisible
+	SchemaRegistered   = "enregistré"   // enregistré par RegisterAll
+)
+
+// CompatibilityResult est le résultat de la vérification d'un schéma
+type CompatibilityResult struct {
+	Topic    string   `json:"topic,omitempty" yaml:"topic,omitempty"`
+	Key      bool     `json:"key,omitempty" yaml:"key,omitempty"`
+	Schema   string   `json:"schema" yaml:"schema"`
+	Subject  string   `json:"subject" yaml:"subject"`
+	Status   string   `json:"status" yaml:"status"`
+	Messages []string `json:"messages,omitempty" yaml:"messages,omitempty"`
+}
+
+// OK indique si le schéma peut être enregistré
+func (r CompatibilityResult) OK() bool {
+	return r.Status != SchemaIncompatible && r.Status != SchemaInvalid
+}
+
+// CheckCompatibility teste le schéma `schemaName` de avroschemas.AvroSchemas contre
+// la dernière version de son sujet (point d'accès de compatibilité du Schema Registry)
+func CheckCompatibility(cfg Config, schemaName string) (CompatibilityResult, error) {
+	return CheckCompatibilityFor(cfg, "", schemaName, false)
+}
 
-	req.SetBasicAuth(cfg.SchemaRegistryKey, cfg.SchemaRegistrySecret)
-	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+// CheckCompatibilityFor teste le schéma `schemaName`, comme schéma de clé ou de
+// valeur de `topic`, contre la dernière version de son sujet (voir SchemaSubject)
+func CheckCompatibilityFor(cfg Config, topic, schemaName string, key bool) (CompatibilityResult, error) {
+	schema, exists := avroschemas.AvroSchemas[schemaName]
+	if !exists {
+		return CompatibilityResult{}, fmt.Errorf("schéma introuvable pour %s", schemaName)
+	}
+	result := CompatibilityResult{Topic: topic, Key: key, Schema: schemaName}
+	if _, err := avro.Parse(schema); err != nil {
+		result.Status, result.Messages = SchemaInvalid, []string{err.Error()}
+		return result, nil
+	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	subject, err := SchemaSubject(cfg, topic, schemaName, key)
 	if err != nil {
-		return fmt.Errorf("erreur lors de l'envoi de la requête : %w", err)
+		return CompatibilityResult{}, err
+	}
+	result.Subject = subject
+
+	compatible, messages, err := cfg.registryClient().CheckCompatibility(context.Background(), subject, schema)
+	var regErr *registry.Error
+	switch {
+	case errors.Is(err, ErrSubjectNotFound):
+		result.Status = SchemaNew
+	case errors.As(err, &regErr) && regErr.Status == http.StatusUnprocessableEntity:
+		result.Status, result.Messages = SchemaInvalid, []string{regErr.Message}
+	case err != nil:
+		return CompatibilityResult{}, err
+	case compatible:
+		result.Status, result.Messages = SchemaCompatible, messages
+	default:
+		result.Status, result.Messages = SchemaIncompatible, messages
 	}
-	defer resp.Body.Close()
+	return result, nil
+}
 
-	body, _ := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("échec de l'enregistrement du schéma : %s", string(body))
+// RegisterAll vérifie la compatibilité des schémas de valeur et de clé déclarés
+// par les topics du manifeste `m`, sous les sujets de ces topics (voir
+// SchemaSubject), puis les enregistre uniquement si tous sont compatibles
+// (jamais avec `dryRun`). Un sujet partagé par plusieurs topics n'est traité
+// qu'une fois. Retourne ErrIncompatibleSchema si au moins un schéma est refusé.
+func RegisterAll(cfg Config, m Manifest, dryRun bool) ([]CompatibilityResult, error) {
+	var results []CompatibilityResult
+	var failed int
+	seen := make(map[string]bool)
+	for _, t := range m.Topics {
+		for i, name := range [2]string{t.Schema, t.KeySchema} { // valeur puis clé
+			if name == "" {
+				continue
+			}
+			result, err := CheckCompatibilityFor(cfg, t.Name, name, i == 1)
+			if err != nil {
+				return results, err
+			}
+			if result.Subject != "" {
+				if seen[result.Subject] {
+					continue
+				}
+				seen[result.Subject] = true
+			}
+			if !result.OK() {
+				failed++
+			}
+			results = append(results, result)
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%w : %d schéma(s) refusé(s), aucun enregistrement", ErrIncompatibleSchema, failed)
+	}
+	if dryRun {
+		return results, nil
 	}
 
-	fmt.Printf("Schéma %s enregistré avec succès\n", schemaName)
-	return nil
+	for i, r := range results {
+		if _, err := RegisterSchemaFor(cfg, r.Topic, r.Schema, r.Key); err != nil {
+			return results, err
+		}
+		results[i].Status = SchemaRegistered
+	}
+	return results, nil
 }
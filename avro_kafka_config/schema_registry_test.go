@@ -0,0 +1,104 @@
+package avro_kafka_config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_schemas/schemas"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry simule le Schema Registry : compatStatus et compatBody forment la réponse du point
+// d'accès de compatibilité ; les enregistrements sont comptés dans `registered`
+func fakeRegistry(t *testing.T, compatStatus int, compatBody string, registered *int) Config {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/compatibility/subjects/"):
+			w.WriteHeader(compatStatus)
+			_, _ = w.Write([]byte(compatBody))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/versions"):
+			*registered++
+			_, _ = w.Write([]byte(`{"id":1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return Config{SchemaRegistryURL: srv.URL}
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	var registered int
+	cfg := fakeRegistry(t, http.StatusOK, `{"is_compatible":false,"messages":["champ email supprimé"]}`, &registered)
+
+	result, err := CheckCompatibility(cfg, schemas.ExampleName)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaIncompatible, result.Status)
+	assert.Equal(t, schemas.ExampleName+"-value", result.Subject)
+	assert.Equal(t, []string{"champ email supprimé"}, result.Messages)
+	assert.False(t, result.OK())
+
+	cfg = fakeRegistry(t, http.StatusNotFound, `{"error_code":40401}`, &registered)
+	result, err = CheckCompatibility(cfg, schemas.ExampleName)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaNew, result.Status)
+	assert.True(t, result.OK())
+}
+
+func TestRegisterAll(t *testing.T) {
+	m := Manifest{Version: ManifestVersion, Topics: []TopicManifest{
+		{Name: "orders", Schema: schemas.ExampleName, KeySchema: schemas.ExampleName},
+		{Name: "audit"},
+	}}
+
+	var registered int
+	cfg := fakeRegistry(t, http.StatusOK, `{"is_compatible":false}`, &registered)
+	_, err := RegisterAll(cfg, m, false)
+	assert.ErrorIs(t, err, ErrIncompatibleSchema)
+	assert.Zero(t, registered)
+
+	cfg = fakeRegistry(t, http.StatusOK, `{"is_compatible":true}`, &registered)
+	results, err := RegisterAll(cfg, m, true)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, SchemaCompatible, results[0].Status)
+	assert.Equal(t, "orders-value", results[0].Subject)
+	assert.Equal(t, "orders-key", results[1].Subject)
+	assert.True(t, results[1].Key)
+	assert.Zero(t, registered)
+
+	results, err = RegisterAll(cfg, m, false)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaRegistered, results[0].Status)
+	assert.Equal(t, len(results), registered)
+
+	// stratégie record : un seul sujet pour la clé et la valeur
+	cfg.SubjectNameStrategy = "record"
+	results, err = RegisterAll(cfg, m, true)
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestSchemaSubject(t *testing.T) {
+	for strategy, want := range map[string]string{
+		"":             "orders-key",
+		"topic":        "orders-key",
+		"record":       "UserCreated",
+		"topic_record": "orders-UserCreated",
+	} {
+		subject, err := SchemaSubject(Config{SubjectNameStrategy: strategy}, "orders", schemas.ExampleName, true)
+		require.NoError(t, err)
+		assert.Equal(t, want, subject, strategy)
+	}
+
+	// sans topic, le nom du schéma en tient lieu
+	subject, err := SchemaSubject(Config{}, "", schemas.ExampleName, false)
+	require.NoError(t, err)
+	assert.Equal(t, schemas.ExampleName+"-value", subject)
+
+	_, err = SchemaSubject(Config{SubjectNameStrategy: "inconnue"}, "orders", schemas.ExampleName, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,279 @@
+package avro_kafka_config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/consumer"
+	"github.com/segmentio/kafka-go"
+)
+
+// TopicSpec décrit un topic à créer
+type TopicSpec struct {
+	Name              string
+	Partitions        int               // 0 : valeur par défaut du broker
+	ReplicationFactor int               // 0 : valeur par défaut du broker
+	Configs           map[string]string // Ex : retention.ms, cleanup.policy
+}
+
+// TopicDescription décrit un topic existant
+type TopicDescription struct {
+	Name       string                 `json:"name" yaml:"name"`
+	Internal   bool                   `json:"internal,omitempty" yaml:"internal,omitempty"`
+	Partitions []PartitionDescription `json:"partitions" yaml:"partitions"`
+	Configs    []ConfigEntry          `json:"configs" yaml:"configs"`
+}
+
+// ReplicationFactor retourne le nombre de réplicas de la première partition
+func (t TopicDescription) ReplicationFactor() int {
+	if len(t.Partitions) == 0 {
+		return 0
+	}
+	return len(t.Partitions[0].Replicas)
+}
+
+// PartitionDescription décrit une partition : leader, réplicas et ISR (identifiants de brokers)
+type PartitionDescription struct {
+	ID       int   `json:"id" yaml:"id"`
+	Leader   int   `json:"leader" yaml:"leader"`
+	Replicas []int `json:"replicas" yaml:"replicas"`
+	ISR      []int `json:"isr" yaml:"isr"`
+	Offline  []int `json:"offline,omitempty" yaml:"offline,omitempty"`
+}
+
+// ConfigEntry est une entrée de configuration d'un topic
+type ConfigEntry struct {
+	Name      string `json:"name" yaml:"name"`
+	Value     string `json:"value" yaml:"value"`
+	Default   bool   `json:"default" yaml:"default"`
+	ReadOnly  bool   `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+	Sensitive bool   `json:"sensitive,omitempty" yaml:"sensitive,omitempty"`
+}
+
+// Sources de configuration renvoyées par DescribeConfigs (v1+)
+const (
+	configSourceDefault = 5 // DEFAULT_CONFIG
+)
+
+// CreateTopic crée un topic Kafka et indique s'il a été créé ; un topic déjà
+// existant n'est pas une erreur (sa configuration n'est pas modifiée, voir Plan et Apply)
+func (kc *KafkaClient) CreateTopic(ctx context.Context, spec TopicSpec) (created bool, err error) {
+	configs := make([]kafka.ConfigEntry, 0, len(spec.Configs))
+	for name, value := range spec.Configs {
+		configs = append(configs, kafka.ConfigEntry{ConfigName: name, ConfigValue: value})
+	}
+
+	res, err := kc.client().CreateTopics(ctx, &kafka.CreateTopicsRequest{
+		Topics: []kafka.TopicConfig{{
+			Topic:             spec.Name,
+			NumPartitions:     orBrokerDefault(spec.Partitions),
+			ReplicationFactor: orBrokerDefault(spec.ReplicationFactor),
+			ConfigEntries:     configs,
+		}},
+	})
+	if err == nil {
+		err = res.Errors[spec.Name]
+	}
+	if errors.Is(err, kafka.TopicAlreadyExists) {
+		kc.logger.Info("topic déjà existant", slog.String("topic", spec.Name))
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("erreur lors de la création du topic %s : %w", spec.Name, err)
+	}
+
+	kc.logger.Info("topic créé", slog.String("topic", spec.Name), slog.Int("partitions", spec.Partitions),
+		slog.Int("replication_factor", spec.ReplicationFactor))
+	return true, nil
+}
+
+// orBrokerDefault remplace une valeur nulle par -1 (valeur par défaut du broker)
+func orBrokerDefault(n int) int {
+	if n <= 0 {
+		return -1
+	}
+	return n
+}
+
+// CreateRetryTopics crée les topics de retry du topic `spec.Name`, un par délai
+// (voir consumer.RetryTopic), avec les partitions et la configuration de `spec` ;
+// les topics déjà existants sont conservés
+func (kc *KafkaClient) CreateRetryTopics(ctx context.Context, spec TopicSpec, tiers []time.Duration) error {
+	for _, delay := range tiers {
+		retrySpec := spec
+		retrySpec.Name = consumer.RetryTopic(spec.Name, delay)
+		if _, err := kc.CreateTopic(ctx, retrySpec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListTopics liste les topics disponibles (hors topics internes), triés par nom
+func (kc *KafkaClient) ListTopics(ctx context.Context) ([]string, error) {
+	res, err := kc.client().Metadata(ctx, &kafka.MetadataRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture des métadonnées : %w", err)
+	}
+
+	var topics []string
+	for _, t := range res.Topics {
+		if !t.Internal {
+			topics = append(topics, t.Name)
+		}
+	}
+	sort.Strings(topics)
+
+	return topics, nil
+}
+
+// DescribeTopic retourne les partitions (leader, réplicas, ISR) et la configuration d'un topic
+func (kc *KafkaClient) DescribeTopic(ctx context.Context, topic string) (TopicDescription, error) {
+	res, err := kc.client().Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return TopicDescription{}, fmt.Errorf("erreur lors de la lecture des métadonnées : %w", err)
+	}
+	if len(res.Topics) == 0 {
+		return TopicDescription{}, fmt.Errorf("topic %s introuvable : %w", topic, kafka.UnknownTopicOrPartition)
+	}
+	t := res.Topics[0]
+	if t.Error != nil {
+		return TopicDescription{}, fmt.Errorf("topic %s : %w", topic, t.Error)
+	}
+
+	desc := TopicDescription{Name: t.Name, Internal: t.Internal}
+	for _, p := range t.Partitions {
+		desc.Partitions = append(desc.Partitions, PartitionDescription{
+			ID:       p.ID,
+			Leader:   p.Leader.ID,
+			Replicas: brokerIDs(p.Replicas),
+			ISR:      brokerIDs(p.Isr),
+			Offline:  brokerIDs(p.OfflineReplicas),
+		})
+	}
+	sort.Slice(desc.Partitions, func(i, j int) bool { return desc.Partitions[i].ID < desc.Partitions[j].ID })
+
+	desc.Configs, err = kc.DescribeTopicConfig(ctx, topic)
+	if err != nil {
+		return TopicDescription{}, err
+	}
+	return desc, nil
+}
+
+func brokerIDs(brokers []kafka.Broker) []int {
+	ids := make([]int, 0, len(brokers))
+	for _, b := range brokers {
+		ids = append(ids, b.ID)
+	}
+	return ids
+}
+
+// DescribeTopicConfig retourne la configuration d'un topic, triée par nom
+// (toutes les entrées si `names` est vide)
+func (kc *KafkaClient) DescribeTopicConfig(ctx context.Context, topic string, names ...string) ([]ConfigEntry, error) {
+	res, err := kc.client().DescribeConfigs(ctx, &kafka.DescribeConfigsRequest{
+		Resources: []kafka.DescribeConfigRequestResource{{
+			ResourceType: kafka.ResourceTypeTopic,
+			ResourceName: topic,
+			ConfigNames:  names,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture de la configuration de %s : %w", topic, err)
+	}
+
+	var entries []ConfigEntry
+	for _, r := range res.Resources {
+		if r.Error != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture de la configuration de %s : %w", topic, r.Error)
+		}
+		for _, e := range r.ConfigEntries {
+			entries = append(entries, ConfigEntry{
+				Name:      e.ConfigName,
+				Value:     e.ConfigValue,
+				Default:   e.IsDefault || e.ConfigSource == configSourceDefault,
+				ReadOnly:  e.ReadOnly,
+				Sensitive: e.IsSensitive,
+			})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// AlterTopicConfig modifie la configuration d'un topic (retention.ms, cleanup.policy,
+// min.insync.replicas…) ; les entrées de `reset` reviennent à la valeur par défaut.
+// Les autres entrées sont conservées.
+func (kc *KafkaClient) AlterTopicConfig(ctx context.Context, topic string, set map[string]string, reset ...string) error {
+	var configs []kafka.IncrementalAlterConfigsRequestConfig
+	for name, value := range set {
+		configs = append(configs, kafka.IncrementalAlterConfigsRequestConfig{
+			Name: name, Value: value, ConfigOperation: kafka.ConfigOperationSet,
+		})
+	}
+	for _, name := range reset {
+		configs = append(configs, kafka.IncrementalAlterConfigsRequestConfig{
+			Name: name, ConfigOperation: kafka.ConfigOperationDelete,
+		})
+	}
+	if len(configs) == 0 {
+		return nil
+	}
+
+	res, err := kc.client().IncrementalAlterConfigs(ctx, &kafka.IncrementalAlterConfigsRequest{
+		Resources: []kafka.IncrementalAlterConfigsRequestResource{{
+			ResourceType: kafka.ResourceTypeTopic,
+			ResourceName: topic,
+			Configs:      configs,
+		}},
+	})
+	if err == nil {
+		for _, r := range res.Resources {
+			if r.Error != nil {
+				err = r.Error
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("erreur lors de la modification de la configuration de %s : %w", topic, err)
+	}
+
+	kc.logger.Info("configuration du topic modifiée", slog.String("topic", topic),
+		slog.Any("set", set), slog.Any("reset", reset))
+	return nil
+}
+
+// CreatePartitions porte le nombre de partitions du topic à `count`
+// (Kafka ne permet pas d'en retirer)
+func (kc *KafkaClient) CreatePartitions(ctx context.Context, topic string, count int) error {
+	res, err := kc.client().CreatePartitions(ctx, &kafka.CreatePartitionsRequest{
+		Topics: []kafka.TopicPartitionsConfig{{Name: topic, Count: int32(count)}},
+	})
+	if err == nil {
+		err = res.Errors[topic]
+	}
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'ajout de partitions à %s : %w", topic, err)
+	}
+
+	kc.logger.Info("partitions ajoutées", slog.String("topic", topic), slog.Int("partitions", count))
+	return nil
+}
+
+// DeleteTopic supprime un topic et tous ses messages
+func (kc *KafkaClient) DeleteTopic(ctx context.Context, topic string) error {
+	res, err := kc.client().DeleteTopics(ctx, &kafka.DeleteTopicsRequest{Topics: []string{topic}})
+	if err == nil {
+		err = res.Errors[topic]
+	}
+	if err != nil {
+		return fmt.Errorf("erreur lors de la suppression du topic %s : %w", topic, err)
+	}
+
+	kc.logger.Info("topic supprimé", slog.String("topic", topic))
+	return nil
+}
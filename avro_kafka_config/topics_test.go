@@ -0,0 +1,20 @@
+package avro_kafka_config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrBrokerDefault(t *testing.T) {
+	assert.Equal(t, -1, orBrokerDefault(0))
+	assert.Equal(t, -1, orBrokerDefault(-3))
+	assert.Equal(t, 3, orBrokerDefault(3))
+}
+
+func TestTopicDescription_ReplicationFactor(t *testing.T) {
+	assert.Equal(t, 0, TopicDescription{}.ReplicationFactor())
+
+	desc := TopicDescription{Partitions: []PartitionDescription{{ID: 0, Replicas: []int{1, 2, 3}}}}
+	assert.Equal(t, 3, desc.ReplicationFactor())
+}
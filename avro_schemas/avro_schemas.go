@@ -1,7 +1,22 @@
+// Package avroschemas centralise les schémas Avro. Les définitions sont les
+// fichiers schemas/*.avsc, embarqués par le package schemas ; les constantes et
+// modèles Go (models) en sont générés par `go generate ./avro_schemas`.
 package avroschemas
 
 import "github.com/METAVENTUS/metaventus-kafka-adapters/avro_schemas/schemas"
 
-var AvroSchemas = map[string]string{
-	schemas.ExampleName: schemas.ExampleSchema,
+//go:generate go run ./cmd/avrogen
+
+// AvroSchemas associe le nom de chaque événement à sa définition JSON autonome
+// (les types partagés de schemas/types n'y figurent pas)
+var AvroSchemas = events()
+
+func events() map[string]string {
+	out := map[string]string{}
+	for _, s := range schemas.All() {
+		if !s.Shared {
+			out[s.Name] = s.JSON
+		}
+	}
+	return out
 }
@@ -0,0 +1,43 @@
+// Commande avrogen : génère les constantes de schéma et les modèles Go à partir
+// des fichiers .avsc. Appelée par `go generate ./avro_schemas`.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/internal/avrogen"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/internal/avsc"
+)
+
+func main() {
+	in := flag.String("in", "schemas", "répertoire des fichiers .avsc (types partagés dans types/)")
+	schemasOut := flag.String("schemas", "schemas/schemas_gen.go", "fichier des constantes de schéma")
+	modelsOut := flag.String("models", "../models/models_gen.go", "fichier des modèles Go")
+	schemasImport := flag.String("schemas-import", "github.com/METAVENTUS/metaventus-kafka-adapters/avro_schemas/schemas",
+		"chemin d'import du package des constantes de schéma")
+	flag.Parse()
+
+	if err := run(*in, *schemasImport, *schemasOut, *modelsOut); err != nil {
+		fmt.Fprintln(os.Stderr, "avrogen :", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, schemasImport, schemasOut, modelsOut string) error {
+	files, err := avsc.Load(os.DirFS(in))
+	if err != nil {
+		return err
+	}
+	out, err := avrogen.Generate(schemasImport, files)
+	if err != nil {
+		return err
+	}
+	for path, data := range map[string][]byte{schemasOut: out.Schemas, modelsOut: out.Models} {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
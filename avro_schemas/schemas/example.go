@@ -1,12 +1,6 @@
 package schemas
 
-const ExampleName = "ModelExample"
-const ExampleSchema = `{
-		"type": "record",
-		"name": "UserCreated",
-		"fields": [
-			{"name": "id", "type": "string"},
-			{"name": "email", "type": "string"},
-			{"name": "name", "type": "string"}
-		]
-	}`
+// Noms historiques du schéma d'exemple (user_created.avsc)
+const ExampleName = ModelExampleName
+
+var ExampleSchema = ModelExampleSchema
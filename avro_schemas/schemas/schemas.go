@@ -0,0 +1,105 @@
+// Package schemas embarque les schémas Avro du dépôt : un fichier .avsc par
+// événement, les types nommés partagés dans types/. Ils sont chargés et validés
+// au démarrage ; les constantes <Type>Name et <Type>Schema ainsi que les modèles
+// Go en sont générés (go generate ./avro_schemas).
+package schemas
+
+import (
+	"embed"
+	"fmt"
+	"reflect"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/internal/avsc"
+	"github.com/hamba/avro"
+)
+
+//go:embed *.avsc types
+var files embed.FS
+
+// Schema est un schéma d'événement ou un type partagé
+type Schema struct {
+	Name     string      // nom du type Go (attribut goType ou nom Avro), clé de avroschemas.AvroSchemas
+	FullName string      // nom complet Avro (namespace.nom)
+	File     string      // fichier .avsc (ex : types/address.avsc)
+	Shared   bool        // type partagé : ni événement, ni sujet du Schema Registry
+	JSON     string      // définition autonome (types d'autres fichiers recopiés)
+	Avro     avro.Schema // schéma analysé
+}
+
+type catalog struct {
+	all        []Schema
+	byName     map[string]Schema
+	byFullName map[string]Schema
+}
+
+// loaded est initialisé avant les variables <Type>Schema qui en dépendent
+var loaded = mustLoad()
+
+// mustLoad charge les fichiers embarqués ; un schéma invalide arrête le programme
+func mustLoad() catalog {
+	loadedFiles, err := avsc.Load(files)
+	if err != nil {
+		panic(fmt.Errorf("schémas Avro embarqués : %w", err))
+	}
+
+	c := catalog{byName: map[string]Schema{}, byFullName: map[string]Schema{}}
+	for _, f := range loadedFiles {
+		s := Schema{
+			Name:     f.GoName,
+			FullName: f.Schema.FullName(),
+			File:     f.Path,
+			Shared:   f.Shared,
+			JSON:     f.JSON,
+			Avro:     f.Schema,
+		}
+		if other, ok := c.byName[s.Name]; ok {
+			panic(fmt.Errorf("schémas Avro embarqués : %s et %s portent le même nom %s", other.File, s.File, s.Name))
+		}
+		c.all = append(c.all, s)
+		c.byName[s.Name] = s
+		c.byFullName[s.FullName] = s
+	}
+	return c
+}
+
+// All retourne les schémas embarqués (événements et types partagés), triés par fichier
+func All() []Schema {
+	return append([]Schema(nil), loaded.all...)
+}
+
+// ByName retourne le schéma de nom `name` (ex : ModelExample)
+func ByName(name string) (Schema, bool) {
+	s, ok := loaded.byName[name]
+	return s, ok
+}
+
+// ByFullName retourne le schéma de nom complet Avro `fullName` (ex : com.metaventus.OrderPlaced)
+func ByFullName(fullName string) (Schema, bool) {
+	s, ok := loaded.byFullName[fullName]
+	return s, ok
+}
+
+// ByGoType retourne le schéma du type de `v` (valeur, pointeur ou reflect.Type),
+// associé par son nom : models.ModelExample → ModelExample
+func ByGoType(v any) (Schema, bool) {
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return Schema{}, false
+	}
+	return ByName(t.Name())
+}
+
+// mustJSON retourne la définition du schéma `name`, généré depuis un fichier embarqué
+func mustJSON(name string) string {
+	s, ok := ByName(name)
+	if !ok {
+		panic(fmt.Errorf("schéma Avro %s absent des fichiers embarqués : relancer go generate ./avro_schemas", name))
+	}
+	return s.JSON
+}
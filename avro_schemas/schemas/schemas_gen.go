@@ -0,0 +1,9 @@
+// Code generated by avrogen. DO NOT EDIT.
+
+package schemas
+
+// ModelExampleName est le nom du schéma UserCreated (user_created.avsc)
+const ModelExampleName = "ModelExample"
+
+// ModelExampleSchema est la définition autonome du schéma UserCreated
+var ModelExampleSchema = mustJSON(ModelExampleName)
@@ -0,0 +1,46 @@
+package schemas_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_schemas/schemas"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/hamba/avro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Chaque schéma embarqué est valide et autonome
+func TestAll_Valid(t *testing.T) {
+	require.NotEmpty(t, schemas.All())
+	for _, s := range schemas.All() {
+		parsed, err := avro.Parse(s.JSON)
+		require.NoError(t, err, s.File)
+		assert.Equal(t, s.Avro.Fingerprint(), parsed.Fingerprint(), s.File)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	s, ok := schemas.ByName(schemas.ExampleName)
+	require.True(t, ok)
+	assert.Equal(t, "UserCreated", s.FullName)
+	assert.Equal(t, "user_created.avsc", s.File)
+	assert.False(t, s.Shared)
+	assert.Equal(t, schemas.ExampleSchema, s.JSON)
+
+	byFullName, ok := schemas.ByFullName("UserCreated")
+	require.True(t, ok)
+	assert.Equal(t, s.Name, byFullName.Name)
+
+	for _, v := range []any{models.ModelExample{}, &models.ModelExample{}, reflect.TypeOf(models.ModelExample{})} {
+		byType, ok := schemas.ByGoType(v)
+		require.True(t, ok)
+		assert.Equal(t, s.Name, byType.Name)
+	}
+
+	_, ok = schemas.ByName("Inconnu")
+	assert.False(t, ok)
+	_, ok = schemas.ByGoType(nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,194 @@
+package consumer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BreakerState est l'état du disjoncteur du Consumer
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // Consommation normale
+	BreakerOpen                         // Trop d'échecs : lecture suspendue jusqu'à la fin du cool-down
+	BreakerHalfOpen                     // Un seul message de test est en cours de traitement
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// DefaultBreakerCooldown est la durée d'ouverture du disjoncteur avant le message de test
+const DefaultBreakerCooldown = 30 * time.Second
+
+// breaker suspend la distribution des messages après `threshold` échecs
+// consécutifs du handler dans `window`, puis laisse passer un seul message de
+// test après `cooldown` : un succès referme le disjoncteur, un échec le rouvre.
+// Un message de test qui n'atteint pas le handler (tombstone ignorée, arrêt…)
+// est libéré par release ; faute de résultat après `cooldown`, un autre message
+// sert de test. Un breaker nil laisse toujours passer les messages.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration // 0 : pas de fenêtre, seuls les échecs consécutifs comptent
+	cooldown  time.Duration
+	onChange  func(BreakerState)
+	now       func() time.Time
+
+	state        BreakerState
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+	probeAt      time.Time     // Début du message de test (demi-ouvert)
+	changed      chan struct{} // Fermé à chaque changement d'état
+}
+
+func newBreaker(threshold int, window, cooldown time.Duration, onChange func(BreakerState)) *breaker {
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &breaker{
+		threshold: threshold,
+		window:    window,
+		cooldown:  cooldown,
+		onChange:  onChange,
+		now:       time.Now,
+		changed:   make(chan struct{}),
+	}
+}
+
+// State retourne l'état courant
+func (b *breaker) State() BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// wait bloque tant que le disjoncteur interdit le traitement d'un message ;
+// retourne false si le contexte est annulé pendant l'attente
+func (b *breaker) wait(ctx context.Context) bool {
+	if b == nil {
+		return true
+	}
+
+	for {
+		b.mu.Lock()
+		var delay time.Duration
+		switch b.state {
+		case BreakerClosed:
+			b.mu.Unlock()
+			return true
+		case BreakerOpen:
+			delay = b.openedAt.Add(b.cooldown).Sub(b.now())
+			if delay <= 0 {
+				// Fin du cool-down : ce message sert de test
+				b.probeAt = b.now()
+				b.setState(BreakerHalfOpen)
+				b.mu.Unlock()
+				return true
+			}
+		case BreakerHalfOpen:
+			delay = b.probeAt.Add(b.cooldown).Sub(b.now())
+			if delay <= 0 {
+				// Message de test sans résultat (perdu) : celui-ci le remplace
+				b.probeAt = b.now()
+				b.mu.Unlock()
+				return true
+			}
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-changed:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// success enregistre un message traité : le disjoncteur se referme
+func (b *breaker) success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	if b.state != BreakerClosed {
+		b.setState(BreakerClosed)
+	}
+}
+
+// release libère le message de test qui n'a pas atteint le handler : le message
+// suivant le remplace sans attendre de nouveau cool-down
+func (b *breaker) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.openedAt = b.now().Add(-b.cooldown)
+		b.setState(BreakerOpen)
+	}
+}
+
+// failure enregistre un échec du handler
+func (b *breaker) failure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	switch b.state {
+	case BreakerHalfOpen:
+		// Le message de test a échoué : nouveau cool-down
+		b.openedAt = now
+		b.setState(BreakerOpen)
+		return
+	case BreakerOpen:
+		return
+	}
+
+	if b.failures == 0 || (b.window > 0 && now.Sub(b.firstFailure) > b.window) {
+		b.failures = 0
+		b.firstFailure = now
+	}
+	b.failures++
+
+	if b.failures >= b.threshold {
+		b.failures = 0
+		b.openedAt = now
+		b.setState(BreakerOpen)
+	}
+}
+
+// setState change l'état et réveille les goroutines en attente (verrou détenu)
+func (b *breaker) setState(state BreakerState) {
+	b.state = state
+	close(b.changed)
+	b.changed = make(chan struct{})
+	if b.onChange != nil {
+		b.onChange(state)
+	}
+}
@@ -0,0 +1,143 @@
+package consumer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/metrics"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker_OpensProbesAndCloses(t *testing.T) {
+	now := time.Now()
+	var states []BreakerState
+	b := newBreaker(3, time.Minute, 10*time.Second, func(s BreakerState) { states = append(states, s) })
+	b.now = func() time.Time { return now }
+
+	b.failure()
+	b.failure()
+	assert.Equal(t, BreakerClosed, b.State())
+	b.failure()
+	assert.Equal(t, BreakerOpen, b.State())
+
+	// pendant le cool-down, aucun message ne passe
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.False(t, b.wait(ctx))
+
+	// après le cool-down, un message de test passe ; son échec rouvre le disjoncteur
+	now = now.Add(11 * time.Second)
+	assert.True(t, b.wait(context.Background()))
+	assert.Equal(t, BreakerHalfOpen, b.State())
+	b.failure()
+	assert.Equal(t, BreakerOpen, b.State())
+
+	// un nouveau test réussi referme le disjoncteur
+	now = now.Add(11 * time.Second)
+	assert.True(t, b.wait(context.Background()))
+	b.success()
+	assert.Equal(t, BreakerClosed, b.State())
+	assert.True(t, b.wait(context.Background()))
+
+	assert.Equal(t, []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}, states)
+}
+
+func TestBreaker_FailuresOutsideWindowDoNotAccumulate(t *testing.T) {
+	now := time.Now()
+	b := newBreaker(2, time.Second, time.Second, nil)
+	b.now = func() time.Time { return now }
+
+	b.failure()
+	now = now.Add(2 * time.Second)
+	b.failure()
+	assert.Equal(t, BreakerClosed, b.State())
+
+	b.failure()
+	assert.Equal(t, BreakerOpen, b.State())
+}
+
+func TestBreaker_NilAlwaysAllows(t *testing.T) {
+	var b *breaker
+	assert.True(t, b.wait(context.Background()))
+	b.failure()
+	assert.Equal(t, BreakerClosed, b.State())
+}
+
+func TestBreaker_ExpiredProbeIsReplaced(t *testing.T) {
+	now := time.Now()
+	b := newBreaker(1, 0, 10*time.Second, nil)
+	b.now = func() time.Time { return now }
+	b.failure()
+
+	now = now.Add(11 * time.Second)
+	assert.True(t, b.wait(context.Background()))
+	assert.Equal(t, BreakerHalfOpen, b.State())
+
+	// message de test en cours : les autres attendent
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.False(t, b.wait(ctx))
+
+	// sans résultat après le cool-down, un autre message le remplace
+	now = now.Add(11 * time.Second)
+	assert.True(t, b.wait(context.Background()))
+	assert.Equal(t, BreakerHalfOpen, b.State())
+}
+
+// Une tombstone ignorée comme message de test ne doit pas bloquer les workers
+func TestBreaker_SkippedTombstoneProbeIsReleased(t *testing.T) {
+	now := time.Now()
+	c := &Consumer[keyedOrder]{
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		metrics: metrics.Nop{},
+		breaker: newBreaker(1, 0, time.Hour, nil),
+	}
+	c.breaker.now = func() time.Time { return now }
+	c.breaker.failure()
+
+	now = now.Add(2 * time.Hour)
+	require.True(t, c.breaker.wait(context.Background()))
+	handle := func(context.Context, keyedOrder) error {
+		t.Fatal("handler appelé pour une tombstone")
+		return nil
+	}
+	assert.True(t, c.process(context.Background(), kafka.Message{Topic: "orders", Key: []byte("42")}, handle))
+
+	// le message suivant sert de test sans attendre un nouveau cool-down
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.True(t, c.breaker.wait(ctx))
+	assert.Equal(t, BreakerHalfOpen, c.breaker.State())
+}
+
+// Un message empoisonné servant de test ne doit pas refermer le disjoncteur
+func TestBreaker_PoisonProbeDoesNotClose(t *testing.T) {
+	now := time.Now()
+	c := &Consumer[keyedOrder]{
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		metrics: metrics.Nop{},
+		breaker: newBreaker(1, 0, time.Hour, nil),
+	}
+	c.breaker.now = func() time.Time { return now }
+	c.breaker.failure()
+
+	now = now.Add(2 * time.Hour)
+	require.True(t, c.breaker.wait(context.Background()))
+	handle := func(context.Context, keyedOrder) error {
+		t.Fatal("handler appelé pour un message empoisonné")
+		return nil
+	}
+	c.process(context.Background(), kafka.Message{Topic: "orders", Key: []byte{0xff}, Value: []byte{0xff}}, handle)
+	assert.NotEqual(t, BreakerClosed, c.breaker.State())
+
+	// le message suivant sert de nouveau test
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.True(t, c.breaker.wait(ctx))
+	assert.Equal(t, BreakerHalfOpen, c.breaker.State())
+}
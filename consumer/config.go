@@ -4,8 +4,15 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// DefaultDrainTimeout est le délai accordé aux handlers en cours lors de Close
+const DefaultDrainTimeout = 30 * time.Second
+
+// DefaultRetryBackoff est le délai avant la première nouvelle tentative d'un handler
+const DefaultRetryBackoff = time.Second
+
 // Config pour le Consumer Kafka
 type Config struct {
 	Brokers         []string
@@ -17,6 +24,37 @@ type Config struct {
 	SASL            bool
 	TLS             bool
 	IsBusinessHours bool
+	DrainTimeout    time.Duration // Délai de drainage à l'arrêt (DefaultDrainTimeout si nul)
+	MaxRetries      int           // Nouvelles tentatives pour les erreurs Retryable (0 : aucune)
+	RetryBackoff    time.Duration // Délai initial entre tentatives, doublé à chaque fois (DefaultRetryBackoff si nul)
+	QuarantineTopic string        // Topic recevant les messages illisibles (vide : journalisés puis ignorés)
+
+	// Retries non bloquants : une erreur Retryable (après MaxRetries) republie le
+	// message sur RetryTopic(Topic, RetryTiers[i]) ; une fois les délais épuisés,
+	// ou pour toute autre erreur, le message part sur DLQTopic. Comme MaxRetries,
+	// les tiers sont opt-in : une erreur non enveloppée par Retryable est
+	// considérée permanente et part directement sur DLQTopic.
+	RetryTiers []time.Duration // Ex : 1m, 10m, 1h (vide : pas de topic de retry)
+	DLQTopic   string          // Dead letter queue (vide : le message est journalisé puis ignoré)
+
+	// Disjoncteur : après BreakerThreshold échecs consécutifs du handler dans
+	// BreakerWindow, la lecture est suspendue pendant BreakerCooldown
+	BreakerThreshold int           // 0 : disjoncteur désactivé
+	BreakerWindow    time.Duration // 0 : pas de fenêtre
+	BreakerCooldown  time.Duration // DefaultBreakerCooldown si nul
+
+	// Limitation : la lecture est ralentie (aucun message n'est perdu)
+	RateLimit       float64 // Messages par seconde (0 : pas de limite)
+	RateBurst       int     // Messages autorisés d'un coup (par défaut : RateLimit arrondi)
+	RateLimitPerKey bool    // Applique RateLimit à chaque clé de message plutôt qu'au Consumer
+	MaxInFlight     int     // Messages lus mais pas encore traités (0 : pas de limite)
+
+	// Schema Registry : les messages au format Confluent sont décodés avec leur schéma
+	// d'écriture, qui doit appartenir au sujet de Topic (vide : Avro brut, schéma de T)
+	SchemaRegistryURL      string
+	SchemaRegistryUsername string
+	SchemaRegistryPassword string
+	SubjectNameStrategy    string // topic (défaut), record ou topic_record
 }
 
 // LoadConfigFromEnv construit la Config en lisant les variables d'environnement
@@ -31,9 +69,24 @@ func LoadConfigFromEnv() Config {
 	sasl := parseBool(os.Getenv("KAFKA_SASL"))
 	tls := parseBool(os.Getenv("KAFKA_TLS"))
 	isBusinessHours := parseBool(os.Getenv("KAFKA_IS_BUSINESS_HOURS"))
+	rateLimitPerKey := parseBool(os.Getenv("KAFKA_RATE_LIMIT_PER_KEY"))
 
 	// Conversion string → int
 	numWorkers := parseInt(os.Getenv("KAFKA_NUM_WORKERS"), 1)
+	maxRetries := parseInt(os.Getenv("KAFKA_MAX_RETRIES"), 0)
+	breakerThreshold := parseInt(os.Getenv("KAFKA_BREAKER_THRESHOLD"), 0)
+	rateBurst := parseInt(os.Getenv("KAFKA_RATE_BURST"), 0)
+	maxInFlight := parseInt(os.Getenv("KAFKA_MAX_IN_FLIGHT"), 0)
+
+	// Conversion string → float
+	rateLimit := parseFloat(os.Getenv("KAFKA_RATE_LIMIT"), 0)
+
+	// Conversion string → durée (ex: "45s")
+	drainTimeout := parseDuration(os.Getenv("KAFKA_DRAIN_TIMEOUT"), DefaultDrainTimeout)
+	retryBackoff := parseDuration(os.Getenv("KAFKA_RETRY_BACKOFF"), DefaultRetryBackoff)
+	retryTiers := parseDurations(os.Getenv("KAFKA_RETRY_TIERS"))
+	breakerWindow := parseDuration(os.Getenv("KAFKA_BREAKER_WINDOW"), 0)
+	breakerCooldown := parseDuration(os.Getenv("KAFKA_BREAKER_COOLDOWN"), DefaultBreakerCooldown)
 
 	cfg := Config{
 		Brokers:         brokers,
@@ -45,6 +98,26 @@ func LoadConfigFromEnv() Config {
 		SASL:            sasl,
 		TLS:             tls,
 		IsBusinessHours: isBusinessHours,
+		DrainTimeout:    drainTimeout,
+		MaxRetries:      maxRetries,
+		RetryBackoff:    retryBackoff,
+		QuarantineTopic: os.Getenv("KAFKA_QUARANTINE_TOPIC"),
+		RetryTiers:      retryTiers,
+		DLQTopic:        os.Getenv("KAFKA_DLQ_TOPIC"),
+
+		BreakerThreshold: breakerThreshold,
+		BreakerWindow:    breakerWindow,
+		BreakerCooldown:  breakerCooldown,
+
+		RateLimit:       rateLimit,
+		RateBurst:       rateBurst,
+		RateLimitPerKey: rateLimitPerKey,
+		MaxInFlight:     maxInFlight,
+
+		SchemaRegistryURL:      os.Getenv("KAFKA_SCHEMA_REGISTRY_URL"),
+		SchemaRegistryUsername: os.Getenv("KAFKA_SCHEMA_REGISTRY_USERNAME"),
+		SchemaRegistryPassword: os.Getenv("KAFKA_SCHEMA_REGISTRY_PASSWORD"),
+		SubjectNameStrategy:    os.Getenv("KAFKA_SUBJECT_NAME_STRATEGY"),
 	}
 	return cfg
 }
@@ -70,3 +143,39 @@ func parseInt(val string, defaultVal int) int {
 	}
 	return i
 }
+
+// parseFloat convertit une chaîne en float64, en renvoyant defaultVal en cas d'erreur
+func parseFloat(val string, defaultVal float64) float64 {
+	if val == "" {
+		return defaultVal
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return defaultVal
+	}
+	return f
+}
+
+// parseDuration convertit une chaîne en durée, en renvoyant defaultVal en cas d'erreur
+func parseDuration(val string, defaultVal time.Duration) time.Duration {
+	if val == "" {
+		return defaultVal
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return defaultVal
+	}
+	return d
+}
+
+// parseDurations convertit une liste de durées séparées par des virgules (ex: "1m,10m,1h"),
+// en ignorant les valeurs invalides
+func parseDurations(val string) []time.Duration {
+	var durations []time.Duration
+	for _, v := range strings.Split(val, ",") {
+		if d := parseDuration(strings.TrimSpace(v), 0); d > 0 {
+			durations = append(durations, d)
+		}
+	}
+	return durations
+}
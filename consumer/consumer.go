@@ -1,15 +1,20 @@
 package consumer
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/METAVENTUS/metaventus-kafka-adapters/internal/ratelimit"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/metrics"
 	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
-	"github.com/hamba/avro"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/registry"
 	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/kafka-go/sasl/plain"
 )
@@ -17,18 +22,89 @@ import (
 // Consumer générique Kafka
 type Consumer[T models.AvroEvent] struct {
 	cfg             Config
-	reader          *kafka.Reader
+	dialer          *kafka.Dialer
 	isBusinessHours bool
+	middlewares     []Middleware[T]
+	hooks           Hooks
+	writer          messageWriter // Quarantaine, retry et DLQ (nil si aucun de ces topics n'est configuré)
+	breaker         *breaker      // nil si Config.BreakerThreshold est nul
+	limiter         *ratelimit.Limiter
+	inFlight        chan struct{} // Sémaphore des messages en cours (nil : pas de limite)
+	logger          *slog.Logger
+	metrics         metrics.Collector
+	serde           *registry.Serde // nil : Avro brut décodé avec le schéma de T
+	tombstones      bool            // Transmet les tombstones au handler (ignorées sinon)
+	status          status
 
-	cancel context.CancelFunc
-	wg     sync.WaitGroup
+	group     *kafka.ConsumerGroup
+	jobs      chan job
+	readersMu sync.Mutex
+	readers   map[TopicPartition]partitionReader                // Readers des partitions assignées
+	newReader func(topic string, partition int) partitionReader // nil : kafka.Reader (remplacé en test)
+
+	stopFetch context.CancelFunc // Arrête la lecture des messages
+	abort     context.CancelFunc // Annule les handlers en cours
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// generation est la partie de kafka.Generation utilisée pour consommer les
+// partitions assignées
+type generation interface {
+	CommitOffsets(offsets map[string]map[int]int64) error
+}
+
+// partitionReader est la partie de kafka.Reader utilisée pour lire une partition
+type partitionReader interface {
+	SetOffset(offset int64) error
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	Stats() kafka.ReaderStats
+	Close() error
+}
+
+// messageWriter est la partie de kafka.Writer utilisée pour republier les messages
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// job est un message lu sur une partition, en attente d'un worker
+type job struct {
+	msg  kafka.Message
+	done func(handled bool) // handled=false : le message ne doit pas être commité
 }
 
 // NewConsumer initialise un Kafka Consumer générique avec un type `T`
-func NewConsumer[T models.AvroEvent](cfg Config) *Consumer[T] {
-	var dialer *kafka.Dialer
+func NewConsumer[T models.AvroEvent](cfg Config, opts ...Option[T]) *Consumer[T] {
+	c := &Consumer[T]{
+		cfg:             cfg,
+		dialer:          kafka.DefaultDialer,
+		isBusinessHours: cfg.IsBusinessHours,
+		logger:          slog.Default(),
+		metrics:         metrics.Nop{},
+		readers:         make(map[TopicPartition]partitionReader),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.logger = c.logger.With(slog.String("group_id", cfg.GroupID))
+
+	if c.serde == nil && cfg.SchemaRegistryURL != "" {
+		strategy, err := registry.ParseStrategy(cfg.SubjectNameStrategy)
+		if err != nil {
+			c.logger.Error("configuration du consumer invalide", slog.Any("error", err))
+			os.Exit(1)
+		}
+		c.serde = registry.NewSerde(registry.NewClient(registry.Config{
+			URL:      cfg.SchemaRegistryURL,
+			Username: cfg.SchemaRegistryUsername,
+			Password: cfg.SchemaRegistryPassword,
+		}), strategy)
+	}
+
 	if cfg.SASL {
-		dialer = &kafka.Dialer{
+		c.dialer = &kafka.Dialer{
 			SASLMechanism: plain.Mechanism{
 				Username: cfg.Username,
 				Password: cfg.Password,
@@ -37,95 +113,538 @@ func NewConsumer[T models.AvroEvent](cfg Config) *Consumer[T] {
 		}
 	}
 
-	conn, err := kafka.Dial("tcp", cfg.Brokers[0])
+	c.limiter = ratelimit.New(cfg.RateLimit, cfg.RateBurst, cfg.RateLimitPerKey)
+	if cfg.MaxInFlight > 0 {
+		c.inFlight = make(chan struct{}, cfg.MaxInFlight)
+	}
+
+	if cfg.BreakerThreshold > 0 {
+		c.breaker = newBreaker(cfg.BreakerThreshold, cfg.BreakerWindow, cfg.BreakerCooldown, c.breakerChanged)
+		c.metrics.CircuitState(cfg.Topic, BreakerClosed.String())
+	}
+
+	if cfg.QuarantineTopic != "" || cfg.DLQTopic != "" || len(cfg.RetryTiers) > 0 {
+		c.writer = c.newWriter()
+	}
+
+	groupConfig := c.groupConfig()
+	if err := groupConfig.Validate(); err != nil {
+		c.logger.Error("configuration du consumer invalide", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	conn, err := c.dialer.Dial("tcp", cfg.Brokers[0])
 	if err != nil {
-		log.Fatalf("❌ Échec de connexion à Kafka: %v", err)
+		c.logger.Error("échec de connexion à Kafka", slog.Any("error", err))
+		os.Exit(1)
 	}
 	defer conn.Close()
-	log.Println("✅ Connexion réussie à Kafka")
-
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:  cfg.Brokers,
-		Topic:    cfg.Topic,
-		GroupID:  cfg.GroupID,
-		Dialer:   dialer, // Authentification SASL/TLS
-		MinBytes: 10e3,
-		MaxBytes: 10e6,
-	})
+	c.logger.Info("connexion à Kafka établie", slog.String("topic", cfg.Topic), slog.Any("brokers", cfg.Brokers))
 
-	return &Consumer[T]{
-		cfg:             cfg,
-		reader:          reader,
-		isBusinessHours: cfg.IsBusinessHours,
+	return c
+}
+
+// groupConfig construit la configuration du groupe de consommateurs
+func (c *Consumer[T]) groupConfig() kafka.ConsumerGroupConfig {
+	return kafka.ConsumerGroupConfig{
+		ID:          c.cfg.GroupID,
+		Brokers:     c.cfg.Brokers,
+		Topics:      c.topics(), // Topic principal et topics de retry
+		Dialer:      c.dialer,   // Authentification SASL/TLS
+		ErrorLogger: kafka.LoggerFunc(c.readerError),
 	}
 }
 
+// readerError reçoit les erreurs internes de kafka-go (connexion, groupe…)
+func (c *Consumer[T]) readerError(msg string, args ...interface{}) {
+	c.status.readerFailed(msg, args...)
+	c.logger.Debug("erreur interne Kafka", slog.String("error", fmt.Sprintf(msg, args...)))
+}
+
 // Start lance la consommation dans une goroutine
 // et démarre les workers en parallèle.
-func (c *Consumer[T]) Start(ctx context.Context, handle func(context.Context, T) error) {
-	workerContext, cancel := context.WithCancel(ctx)
-	c.cancel = cancel
+// Le handler est enveloppé par les middlewares configurés, puis par Recover
+// pour qu'une panique ne fasse jamais tomber un worker.
+// Les handlers reçoivent un contexte qui n'est annulé qu'à l'expiration du
+// délai de drainage de Close, et non à l'annulation de `ctx`.
+func (c *Consumer[T]) Start(ctx context.Context, handle Handler[T]) {
+	fetchCtx, stopFetch := context.WithCancel(ctx)
+	handleCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	c.stopFetch, c.abort = stopFetch, abort
+
+	group, err := kafka.NewConsumerGroup(c.groupConfig())
+	if err != nil {
+		c.logger.Error("impossible de rejoindre le groupe de consommateurs", slog.Any("error", err))
+		c.hooks.error(ctx, err)
+		return
+	}
+	c.group = group
+
+	handle = Recover[T]()(Chain(handle, c.middlewares...))
+	c.jobs = make(chan job)
+
 	// On démarre N workers
 	for i := 0; i < c.cfg.NumWorkers; i++ {
 		c.wg.Add(1)
-		go c.worker(workerContext, handle)
+		go c.worker(fetchCtx, handleCtx, handle)
 	}
+
+	c.wg.Add(1)
+	go c.run(fetchCtx, handleCtx)
+
+	c.wg.Add(1)
+	go c.collectStats(fetchCtx)
+
+	c.status.setRunning(true)
+	c.hooks.start(ctx)
 }
 
-// Close arrête la consommation
-// - Annule le contexte pour que les workers s'arrêtent
-// - Attend que tous les workers finissent
-// - Ferme le reader Kafka
+// Close arrête la consommation proprement
+// - Arrête la lecture de nouveaux messages
+// - Laisse les handlers en cours terminer (au plus DrainTimeout, puis annule leur contexte)
+// - Committe les offsets des messages traités et libère les partitions
+// - Quitte le groupe de consommateurs
+// Close peut être appelé sans Start, et plusieurs fois.
 func (c *Consumer[T]) Close() error {
-	// Annule le contexte pour que les goroutines worker s'arrêtent
-	c.cancel()
+	c.closeOnce.Do(func() {
+		c.closeErr = c.shutdown()
+	})
+	return c.closeErr
+}
 
-	// Attend la fin de tous les workers
-	c.wg.Wait()
+func (c *Consumer[T]) shutdown() error {
+	c.status.setRunning(false)
 
-	// Ferme le reader
-	return c.reader.Close()
+	if c.stopFetch == nil {
+		return nil
+	}
+
+	// Plus aucun message n'est lu
+	c.stopFetch()
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	drainTimeout := c.cfg.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = DefaultDrainTimeout
+	}
+
+	select {
+	case <-done:
+	case <-time.After(drainTimeout):
+		c.logger.Warn("délai de drainage dépassé : annulation des handlers en cours",
+			slog.Duration("drain_timeout", drainTimeout))
+		c.abort()
+		<-done
+	}
+	c.abort()
+
+	var errs []error
+	if c.group != nil {
+		// Quitte le groupe
+		errs = append(errs, c.group.Close())
+	}
+	if c.writer != nil {
+		errs = append(errs, c.writer.Close())
+	}
+	err := errors.Join(errs...)
+	c.hooks.stop(context.Background())
+	return err
 }
 
-// worker : exécute la consommation Kafka pour un worker
-func (c *Consumer[T]) worker(ctx context.Context, handle func(context.Context, T) error) {
+// run attend chaque nouvelle génération du groupe et en consomme les partitions
+func (c *Consumer[T]) run(fetchCtx, handleCtx context.Context) {
 	defer c.wg.Done()
 
 	for {
-		select {
-		case <-ctx.Done(): // Vérifie si le contexte est annulé
-			log.Println("Arrêt du worker Kafka (ctx.Done)")
-			return
-
-		default:
-			msg, err := c.reader.ReadMessage(ctx)
-			if err != nil {
-				log.Printf("Erreur de lecture Kafka : %v", err)
-				continue
+		gen, err := c.group.Next(fetchCtx)
+		if err != nil {
+			if fetchCtx.Err() != nil {
+				return
 			}
+			c.status.readerFailed("%v", err)
+			c.logger.Error("erreur du groupe de consommateurs", slog.Any("error", err))
+			c.hooks.error(fetchCtx, err)
+			continue
+		}
+
+		c.wg.Add(1)
+		gen.Start(func(genCtx context.Context) {
+			defer c.wg.Done()
+			c.consumeGeneration(fetchCtx, genCtx, handleCtx, gen.ID, gen.Assignments, gen)
+		})
+	}
+}
+
+// consumeGeneration lit les partitions assignées jusqu'à la fin de la génération
+// (rééquilibrage) ou l'arrêt du Consumer, puis notifie leur révocation
+func (c *Consumer[T]) consumeGeneration(fetchCtx, genCtx, handleCtx context.Context, id int32,
+	assignments map[string][]kafka.PartitionAssignment, gen generation) {
+	ctx, cancel := context.WithCancel(fetchCtx)
+	defer cancel()
+	stop := context.AfterFunc(genCtx, cancel)
+	defer stop()
+
+	var partitions []TopicPartition
+	for topic, topicAssignments := range assignments {
+		for _, a := range topicAssignments {
+			partitions = append(partitions, TopicPartition{Topic: topic, Partition: a.ID})
+		}
+	}
+	sort.Slice(partitions, func(i, j int) bool {
+		if partitions[i].Topic != partitions[j].Topic {
+			return partitions[i].Topic < partitions[j].Topic
+		}
+		return partitions[i].Partition < partitions[j].Partition
+	})
+
+	c.status.setGroupMember(true)
+	c.status.brokerReached()
+	c.logger.Info("partitions assignées", slog.Int("generation", int(id)), slog.Any("partitions", partitions))
+	c.hooks.partitionsAssigned(ctx, partitions)
+
+	var wg sync.WaitGroup
+	for topic, topicAssignments := range assignments {
+		for _, a := range topicAssignments {
+			wg.Add(1)
+			go func(topic string, a kafka.PartitionAssignment) {
+				defer wg.Done()
+				c.consumePartition(ctx, gen, topic, a)
+			}(topic, a)
+		}
+	}
+	wg.Wait()
 
-			// Vérifier si on est dans la plage horaire ou non (skip si hors plage)
-			if c.isBusinessHours && !isBusinessHours(time.Now()) {
-				log.Println("Hors plage horaire : skip le message temporairement")
-				<-time.After(10 * time.Minute)
-				continue
+	c.logger.Info("partitions révoquées", slog.Int("generation", int(id)), slog.Any("partitions", partitions))
+	c.hooks.partitionsRevoked(handleCtx, partitions)
+	c.status.setGroupMember(false)
+}
+
+// consumePartition lit une partition et transmet ses messages aux workers ;
+// à la fin, attend les messages en cours puis committe le dernier offset traité
+func (c *Consumer[T]) consumePartition(ctx context.Context, gen generation, topic string, a kafka.PartitionAssignment) {
+	tp := TopicPartition{Topic: topic, Partition: a.ID}
+
+	reader := c.partitionReader(topic, a.ID)
+	defer reader.Close()
+
+	if err := reader.SetOffset(a.Offset); err != nil {
+		c.status.readerFailed("%v", err)
+		c.logger.Error("impossible de positionner l'offset", slog.String("topic", topic), slog.Int("partition", a.ID), slog.Any("error", err))
+		c.hooks.error(ctx, err)
+		return
+	}
+
+	c.trackReader(tp, reader)
+	defer c.untrackReader(tp)
+
+	tracker := newOffsetTracker()
+	commit := func() {
+		err := tracker.commit(func(offset int64) error {
+			if err := gen.CommitOffsets(map[string]map[int]int64{topic: {a.ID: offset}}); err != nil {
+				return err
 			}
+			c.status.brokerReached()
+			return nil
+		})
+		if err != nil {
+			c.status.readerFailed("%v", err)
+			c.logger.Error("erreur de commit Kafka", slog.String("topic", topic), slog.Int("partition", a.ID), slog.Any("error", err))
+			c.hooks.error(ctx, err)
+		}
+	}
+
+	var inflight sync.WaitGroup
+	defer func() {
+		inflight.Wait()
+		commit()
+	}()
 
-			var event T
-			decoder, err := avro.NewDecoder(event.GetSchema(), bytes.NewReader(msg.Value))
-			if err != nil {
-				log.Printf("Erreur chargement décodeur Avro : %v", err)
-				continue
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
 			}
+			c.status.readerFailed("%v", err)
+			c.logger.Error("erreur de lecture Kafka", slog.String("topic", topic), slog.Int("partition", a.ID), slog.Any("error", err))
+			c.hooks.error(ctx, err)
+			continue
+		}
+
+		c.status.fetched()
+		c.metrics.MessageConsumed(msg.Topic, msg.Partition, len(msg.Value))
 
-			if err = decoder.Decode(&event); err != nil {
-				log.Printf("Erreur de décodage Avro : %v", err)
-				continue
+		// Hors plage horaire : on attend la réouverture avant de traiter ce message
+		if !c.waitBusinessHours(ctx, msg) {
+			// Arrêt pendant l'attente : le message non commité sera relu
+			return
+		}
+
+		// Message republié pour une nouvelle tentative : on attend son échéance
+		if !c.waitUntilDue(ctx, msg) {
+			return
+		}
+
+		// Disjoncteur ouvert : on attend le cool-down (ou le résultat du message de test)
+		if !c.breaker.wait(ctx) {
+			return
+		}
+
+		// Limites de débit et de messages en cours : on ralentit la lecture
+		if err := c.limiter.Wait(ctx, string(msg.Key)); err != nil {
+			c.breaker.release()
+			return
+		}
+		if !c.acquireInFlight(ctx) {
+			c.breaker.release()
+			return
+		}
+
+		tracker.fetched(msg.Offset)
+		inflight.Add(1)
+		j := job{msg: msg, done: func(handled bool) {
+			if handled {
+				tracker.done(msg.Offset)
+				commit()
 			}
+			c.releaseInFlight()
+			inflight.Done()
+		}}
+
+		select {
+		case c.jobs <- j:
+		case <-ctx.Done():
+			c.breaker.release()
+			c.releaseInFlight()
+			inflight.Done()
+			return
+		}
+	}
+}
+
+// worker : traite les messages lus sur les partitions assignées
+// fetchCtx arrête le worker, handleCtx est transmis aux handlers.
+func (c *Consumer[T]) worker(fetchCtx, handleCtx context.Context, handle Handler[T]) {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-fetchCtx.Done():
+			c.logger.Debug("arrêt du worker Kafka")
+			return
+		case j := <-c.jobs:
+			commit := c.process(handleCtx, j.msg, handle)
+			// Les messages en échec sont aussi commités (ils ont été journalisés),
+			// sauf si le handler a été interrompu par l'expiration du drainage
+			j.done(commit && handleCtx.Err() == nil)
+		}
+	}
+}
+
+// process décode le message puis appelle le handler ;
+// retourne false si le message ne doit pas être commité
+func (c *Consumer[T]) process(ctx context.Context, msg kafka.Message, handle Handler[T]) bool {
+	m := Message{Message: msg, GroupID: c.cfg.GroupID, Deleted: isTombstone(msg)}
+	ctx = ContextWithMessage(ctx, m)
+
+	if m.Deleted && !c.tombstones {
+		// Le handler n'est pas appelé : rien à apprendre pour le disjoncteur
+		c.breaker.release()
+		c.status.handled()
+		c.logger.Debug("tombstone ignorée", messageAttrs(msg)...)
+		return true
+	}
+
+	event, key, err := c.decode(ctx, msg)
+	switch {
+	case errors.Is(err, ErrPoisonMessage):
+		c.metrics.DecodeError(msg.Topic)
+		c.status.failed(err)
+		c.logger.Error("erreur de décodage Avro", append(messageAttrs(msg), slog.Any("error", err))...)
+		c.hooks.error(ctx, err)
+		// Un message illisible ne dit rien de l'état des dépendances du handler
+		c.breaker.release()
+		return c.quarantine(ctx, msg, err)
+	case errors.Is(err, errLocalSchema):
+		c.metrics.DecodeError(msg.Topic)
+		c.status.failed(err)
+		c.logger.Error("erreur de chargement du décodeur Avro", append(messageAttrs(msg), slog.Any("error", err))...)
+		c.hooks.error(ctx, err)
+		c.breaker.release()
+		return true
+	case err != nil:
+		c.metrics.DecodeError(msg.Topic)
+		c.status.failed(err)
+		c.logger.Error("Schema Registry injoignable : message non commité", append(messageAttrs(msg), slog.Any("error", err))...)
+		c.hooks.error(ctx, err)
+		c.breaker.release()
+		return false
+	}
+	if key != nil {
+		m.DecodedKey = key
+		ctx = ContextWithMessage(ctx, m)
+	}
+
+	err = c.handleWithRetry(ctx, msg, event, handle)
+	switch {
+	case err == nil:
+		c.breaker.success()
+		c.status.handled()
+	case errors.Is(err, ErrSkip):
+		c.breaker.success()
+		c.status.handled()
+		c.logger.Debug("message ignoré par le handler", append(messageAttrs(msg), slog.Any("reason", err))...)
+	default:
+		retryable := errors.Is(err, ErrRetryable) && !errors.Is(err, ErrPermanent)
+		c.breaker.failure()
+		c.metrics.HandlerError(msg.Topic)
+		c.status.failed(err)
+		c.logger.Error("erreur dans le handler", append(messageAttrs(msg),
+			slog.Bool("permanent", !retryable),
+			slog.Any("error", err))...)
+		c.hooks.error(ctx, err)
+		return c.forward(ctx, msg, err, retryable)
+	}
+	return true
+}
+
+// handleWithRetry appelle le handler et le rappelle, avec un délai exponentiel,
+// tant qu'il retourne une erreur Retryable (au plus MaxRetries fois)
+func (c *Consumer[T]) handleWithRetry(ctx context.Context, msg kafka.Message, event T, handle Handler[T]) error {
+	backoff := c.cfg.RetryBackoff
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := handle(ctx, event)
+		c.metrics.HandlerDuration(msg.Topic, time.Since(start))
+
+		if err == nil || !errors.Is(err, ErrRetryable) || errors.Is(err, ErrPermanent) || errors.Is(err, ErrSkip) {
+			return err
+		}
+		if attempt > c.cfg.MaxRetries {
+			return err
+		}
+
+		c.metrics.Retry(msg.Topic)
+		c.logger.Warn("nouvelle tentative de traitement", append(messageAttrs(msg),
+			slog.Int("attempt", attempt), slog.Duration("backoff", backoff), slog.Any("error", err))...)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// acquireInFlight réserve une place pour un message en cours (si MaxInFlight est défini)
+func (c *Consumer[T]) acquireInFlight(ctx context.Context) bool {
+	if c.inFlight == nil {
+		return true
+	}
+	select {
+	case c.inFlight <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (c *Consumer[T]) releaseInFlight() {
+	if c.inFlight != nil {
+		<-c.inFlight
+	}
+}
+
+// breakerChanged publie chaque changement d'état du disjoncteur
+func (c *Consumer[T]) breakerChanged(state BreakerState) {
+	c.metrics.CircuitState(c.cfg.Topic, state.String())
+	switch state {
+	case BreakerOpen:
+		c.logger.Warn("disjoncteur ouvert : lecture suspendue",
+			slog.String("topic", c.cfg.Topic), slog.Duration("cooldown", c.breaker.cooldown))
+	case BreakerHalfOpen:
+		c.logger.Info("disjoncteur semi-ouvert : traitement d'un message de test", slog.String("topic", c.cfg.Topic))
+	case BreakerClosed:
+		c.logger.Info("disjoncteur refermé : reprise de la lecture", slog.String("topic", c.cfg.Topic))
+	}
+}
+
+// waitBusinessHours bloque tant qu'on est hors plage horaire (si l'option est active) ;
+// retourne false si le contexte est annulé pendant l'attente
+func (c *Consumer[T]) waitBusinessHours(ctx context.Context, msg kafka.Message) bool {
+	if !c.isBusinessHours || isBusinessHours(time.Now()) {
+		return true
+	}
 
-			if err = handle(ctx, event); err != nil {
-				log.Printf("Erreur dans handle : %v", err)
+	c.logger.Info("hors plage horaire : consommation suspendue", messageAttrs(msg)...)
+	c.status.setOutsideBusinessHours(true)
+	defer c.status.setOutsideBusinessHours(false)
+
+	for !isBusinessHours(time.Now()) {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(10 * time.Minute):
+		}
+	}
+	return true
+}
+
+// partitionReader crée le Reader d'une partition assignée
+func (c *Consumer[T]) partitionReader(topic string, partition int) partitionReader {
+	if c.newReader != nil {
+		return c.newReader(topic, partition)
+	}
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     c.cfg.Brokers,
+		Topic:       topic,
+		Partition:   partition,
+		Dialer:      c.dialer,
+		MinBytes:    10e3,
+		MaxBytes:    10e6,
+		ErrorLogger: kafka.LoggerFunc(c.readerError),
+	})
+}
+
+func (c *Consumer[T]) trackReader(tp TopicPartition, reader partitionReader) {
+	c.readersMu.Lock()
+	defer c.readersMu.Unlock()
+	c.readers[tp] = reader
+}
+
+func (c *Consumer[T]) untrackReader(tp TopicPartition) {
+	c.readersMu.Lock()
+	defer c.readersMu.Unlock()
+	delete(c.readers, tp)
+}
+
+// collectStats transmet périodiquement les statistiques des readers (lag par partition…) au collecteur
+func (c *Consumer[T]) collectStats(ctx context.Context) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(metrics.StatsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.readersMu.Lock()
+			for _, reader := range c.readers {
+				c.metrics.ReaderStats(reader.Stats())
 			}
+			c.readersMu.Unlock()
 		}
 	}
 }
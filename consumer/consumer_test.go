@@ -0,0 +1,70 @@
+package consumer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startGeneration consomme `partition` jusqu'à l'arrêt du Consumer, comme run
+func (h *groupHarness) startGeneration(fetchCtx, handleCtx context.Context, partition kafka.PartitionAssignment) {
+	h.c.wg.Add(1)
+	go func() {
+		defer h.c.wg.Done()
+		h.c.consumeGeneration(fetchCtx, context.Background(), handleCtx, 1,
+			map[string][]kafka.PartitionAssignment{"orders": {partition}}, h.gen)
+	}()
+}
+
+func TestClose_WaitsForInFlightHandlers(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var handlerErr error
+	h, fetchCtx, handleCtx := newGroupHarness(t, func(ctx context.Context, _ keyedOrder) error {
+		close(started)
+		<-release
+		handlerErr = ctx.Err()
+		return nil
+	})
+	h.c.cfg.DrainTimeout = 5 * time.Second
+	p0 := TopicPartition{Topic: "orders", Partition: 0}
+	h.startGeneration(fetchCtx, handleCtx, kafka.PartitionAssignment{ID: 0, Offset: 10})
+	h.reader(t, p0).messages <- orderMessage(t, 0, 10, "a")
+	<-started
+
+	closed := make(chan error)
+	go func() { closed <- h.c.Close() }()
+	select {
+	case <-closed:
+		t.Fatal("Close n'a pas attendu le handler en cours")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-closed)
+	assert.NoError(t, handlerErr, "contexte du handler annulé alors que le drainage a abouti")
+	assert.Equal(t, int64(11), h.gen.committed(p0))
+}
+
+func TestClose_DrainTimeoutCancelsHandlers(t *testing.T) {
+	started := make(chan struct{})
+	h, fetchCtx, handleCtx := newGroupHarness(t, func(ctx context.Context, _ keyedOrder) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	h.c.cfg.DrainTimeout = 50 * time.Millisecond
+	p0 := TopicPartition{Topic: "orders", Partition: 0}
+	h.startGeneration(fetchCtx, handleCtx, kafka.PartitionAssignment{ID: 0, Offset: 10})
+	h.reader(t, p0).messages <- orderMessage(t, 0, 10, "a")
+	<-started
+
+	start := time.Now()
+	require.NoError(t, h.c.Close())
+	assert.Less(t, time.Since(start), 5*time.Second)
+	// le message interrompu n'est pas commité : le groupe reprendra à son offset
+	assert.Equal(t, int64(10), h.gen.committed(p0))
+}
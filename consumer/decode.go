@@ -0,0 +1,76 @@
+package consumer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/registry"
+	"github.com/hamba/avro"
+	"github.com/segmentio/kafka-go"
+)
+
+// errLocalSchema signale un schéma Avro local (GetSchema, GetKeySchema) illisible
+var errLocalSchema = errors.New("schéma Avro local invalide")
+
+// decode décode la valeur du message (événement vide pour une tombstone) et, si T
+// implémente models.KeyedEvent, sa clé (nil si le message n'a pas de clé). Les
+// erreurs propres au message enveloppent ErrPoisonMessage, celles du schéma local
+// errLocalSchema ; les autres (Schema Registry injoignable) sont transitoires.
+func (c *Consumer[T]) decode(ctx context.Context, msg kafka.Message) (T, any, error) {
+	var event T
+	if !isTombstone(msg) {
+		if err := c.decodeAvro(ctx, event.GetSchema(), false, msg.Value, &event); err != nil {
+			return event, nil, err
+		}
+	}
+
+	keyed, ok := any(event).(models.KeyedEvent)
+	if !ok || len(msg.Key) == 0 {
+		return event, nil, nil
+	}
+	key := newKey(keyed)
+	if err := c.decodeAvro(ctx, keyed.GetKeySchema(), true, msg.Key, key); err != nil {
+		return event, nil, fmt.Errorf("clé : %w", err)
+	}
+	return event, reflect.ValueOf(key).Elem().Interface(), nil
+}
+
+// decodeAvro décode `data` dans `v` : au format Confluent avec un Schema Registry
+// (les messages des topics de retry étant des copies de ceux de Topic, le sujet
+// attendu est toujours celui de Topic), sinon en Avro brut avec `schema`
+func (c *Consumer[T]) decodeAvro(ctx context.Context, schema string, key bool, data []byte, v any) error {
+	if c.serde != nil {
+		err := c.serde.Deserialize(ctx, c.cfg.Topic, key, data, v)
+		if errors.Is(err, registry.ErrInvalidMessage) {
+			return fmt.Errorf("%w : %w", ErrPoisonMessage, err)
+		}
+		return err
+	}
+
+	decoder, err := avro.NewDecoder(schema, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w : %w", errLocalSchema, err)
+	}
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w : %w", ErrPoisonMessage, err)
+	}
+	return nil
+}
+
+// newKey retourne un pointeur vers une valeur du type retourné par Key()
+// (any, décodé en maps et slices, si Key() retourne nil)
+func newKey(keyed models.KeyedEvent) any {
+	if t := reflect.TypeOf(keyed.Key()); t != nil {
+		return reflect.New(t).Interface()
+	}
+	return new(any)
+}
+
+// isTombstone indique si le message est une tombstone (valeur nulle ou vide)
+func isTombstone(msg kafka.Message) bool {
+	return len(msg.Value) == 0
+}
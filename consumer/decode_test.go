@@ -0,0 +1,88 @@
+package consumer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/metrics"
+	"github.com/hamba/avro"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const orderKeySchema = `{"type": "record", "name": "OrderKey", "fields": [{"name": "id", "type": "string"}]}`
+
+type orderKey struct {
+	ID string `avro:"id"`
+}
+
+// keyedOrder est un événement dont la clé est encodée en Avro
+type keyedOrder struct {
+	ID     string `avro:"id"`
+	Amount int    `avro:"amount"`
+}
+
+func (keyedOrder) GetSchema() string {
+	return `{"type": "record", "name": "Order", "fields": [
+		{"name": "id", "type": "string"}, {"name": "amount", "type": "int"}]}`
+}
+func (o keyedOrder) PartitionKey() string { return o.ID }
+func (keyedOrder) GetKeySchema() string   { return orderKeySchema }
+func (o keyedOrder) Key() any             { return orderKey{ID: o.ID} }
+
+func TestProcess_DecodesKey(t *testing.T) {
+	c := &Consumer[keyedOrder]{logger: slog.New(slog.NewTextHandler(io.Discard, nil)), metrics: metrics.Nop{}}
+	order := keyedOrder{ID: "42", Amount: 10}
+	value, err := avro.Marshal(avro.MustParse(order.GetSchema()), order)
+	require.NoError(t, err)
+	key, err := avro.Marshal(avro.MustParse(orderKeySchema), order.Key())
+	require.NoError(t, err)
+
+	var got orderKey
+	commit := c.process(context.Background(), kafka.Message{Topic: "orders", Key: key, Value: value},
+		func(ctx context.Context, event keyedOrder) error {
+			var ok bool
+			got, ok = KeyFromContext[orderKey](ctx)
+			assert.True(t, ok)
+			assert.Equal(t, order, event)
+			return nil
+		})
+	assert.True(t, commit)
+	assert.Equal(t, orderKey{ID: "42"}, got)
+
+	// une clé illisible est un message empoisonné
+	var reported error
+	c.hooks = Hooks{OnError: func(_ context.Context, err error) { reported = err }}
+	c.process(context.Background(), kafka.Message{Topic: "orders", Key: []byte{0xff}, Value: value},
+		func(context.Context, keyedOrder) error { return nil })
+	assert.ErrorIs(t, reported, ErrPoisonMessage)
+}
+
+func TestProcess_Tombstones(t *testing.T) {
+	c := &Consumer[keyedOrder]{logger: slog.New(slog.NewTextHandler(io.Discard, nil)), metrics: metrics.Nop{}}
+	key, err := avro.Marshal(avro.MustParse(orderKeySchema), orderKey{ID: "42"})
+	require.NoError(t, err)
+	msg := kafka.Message{Topic: "orders", Key: key}
+
+	// sans WithTombstones, la tombstone est commitée sans appeler le handler
+	var calls int
+	handle := func(ctx context.Context, event keyedOrder) error {
+		calls++
+		m, _ := MessageFromContext(ctx)
+		assert.True(t, m.Deleted)
+		assert.Equal(t, keyedOrder{}, event)
+		k, ok := KeyFromContext[orderKey](ctx)
+		assert.True(t, ok)
+		assert.Equal(t, orderKey{ID: "42"}, k)
+		return nil
+	}
+	assert.True(t, c.process(context.Background(), msg, handle))
+	assert.Zero(t, calls)
+
+	WithTombstones[keyedOrder]()(c)
+	assert.True(t, c.process(context.Background(), msg, handle))
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,158 @@
+package consumer
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+)
+
+// DedupStore mémorise les clés d'idempotence des messages déjà traités
+type DedupStore interface {
+	// Seen indique si la clé a déjà été traitée avec succès
+	Seen(ctx context.Context, key string) (bool, error)
+	// Mark enregistre la clé après un traitement réussi
+	Mark(ctx context.Context, key string) error
+}
+
+// KeyFunc calcule la clé d'idempotence d'un message
+// (une clé vide désactive la déduplication pour ce message)
+type KeyFunc[T models.AvroEvent] func(ctx context.Context, event T) string
+
+// KeyFromHeader utilise la valeur d'un header Kafka comme clé d'idempotence
+func KeyFromHeader[T models.AvroEvent](name string) KeyFunc[T] {
+	return func(ctx context.Context, _ T) string {
+		msg, ok := MessageFromContext(ctx)
+		if !ok {
+			return ""
+		}
+		return msg.Header(name)
+	}
+}
+
+// KeyFromPartitionKey utilise PartitionKey() de l'événement comme clé d'idempotence
+func KeyFromPartitionKey[T models.AvroEvent]() KeyFunc[T] {
+	return func(_ context.Context, event T) string {
+		return event.PartitionKey()
+	}
+}
+
+// Dedup ignore les messages dont la clé a déjà été traitée avec succès.
+// La clé n'est enregistrée qu'après un appel réussi au handler : deux workers
+// recevant simultanément le même message peuvent donc encore le traiter deux fois.
+func Dedup[T models.AvroEvent](store DedupStore, key KeyFunc[T]) Middleware[T] {
+	return func(next Handler[T]) Handler[T] {
+		return func(ctx context.Context, event T) error {
+			k := key(ctx, event)
+			if k == "" {
+				return next(ctx, event)
+			}
+
+			seen, err := store.Seen(ctx, k)
+			if err != nil {
+				return fmt.Errorf("erreur de lecture du store de déduplication : %w", err)
+			}
+			if seen {
+				return nil
+			}
+
+			if err = next(ctx, event); err != nil {
+				return err
+			}
+
+			if err = store.Mark(ctx, k); err != nil {
+				return fmt.Errorf("erreur d'écriture dans le store de déduplication : %w", err)
+			}
+			return nil
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Store en mémoire (LRU + TTL)
+// -----------------------------------------------------------------------------
+
+// MemoryDedupStore conserve au plus `capacity` clés, chacune pendant `ttl`
+type MemoryDedupStore struct {
+	capacity int
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front = clé la plus récemment utilisée
+}
+
+type dedupEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// NewMemoryDedupStore crée un store en mémoire (ttl <= 0 : pas d'expiration)
+func NewMemoryDedupStore(capacity int, ttl time.Duration) *MemoryDedupStore {
+	if capacity <= 0 {
+		capacity = 10000
+	}
+	return &MemoryDedupStore{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Seen indique si la clé est présente et non expirée
+func (s *MemoryDedupStore) Seen(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return false, nil
+	}
+
+	entry := elem.Value.(*dedupEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		s.order.Remove(elem)
+		delete(s.entries, key)
+		return false, nil
+	}
+
+	s.order.MoveToFront(elem)
+	return true, nil
+}
+
+// Mark enregistre la clé et évince la plus ancienne si la capacité est atteinte
+func (s *MemoryDedupStore) Mark(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expiresAt time.Time
+	if s.ttl > 0 {
+		expiresAt = time.Now().Add(s.ttl)
+	}
+
+	if elem, ok := s.entries[key]; ok {
+		elem.Value.(*dedupEntry).expiresAt = expiresAt
+		s.order.MoveToFront(elem)
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(&dedupEntry{key: key, expiresAt: expiresAt})
+
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*dedupEntry).key)
+	}
+	return nil
+}
+
+// Len retourne le nombre de clés actuellement mémorisées
+func (s *MemoryDedupStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
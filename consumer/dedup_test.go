@@ -0,0 +1,77 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryDedupStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryDedupStore(2, 0)
+
+	require.NoError(t, store.Mark(ctx, "a"))
+	require.NoError(t, store.Mark(ctx, "b"))
+
+	// "a" devient la clé la plus récente, "b" sera évincée
+	seen, err := store.Seen(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, seen)
+
+	require.NoError(t, store.Mark(ctx, "c"))
+	assert.Equal(t, 2, store.Len())
+
+	seen, _ = store.Seen(ctx, "b")
+	assert.False(t, seen)
+	seen, _ = store.Seen(ctx, "a")
+	assert.True(t, seen)
+}
+
+func TestMemoryDedupStore_Expires(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryDedupStore(10, 10*time.Millisecond)
+
+	require.NoError(t, store.Mark(ctx, "a"))
+	time.Sleep(20 * time.Millisecond)
+
+	seen, err := store.Seen(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, seen)
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestDedup_SkipsAlreadyHandledMessages(t *testing.T) {
+	store := NewMemoryDedupStore(10, time.Hour)
+
+	var calls int
+	failNext := true
+	handler := Dedup(store, KeyFromHeader[models.ModelExample]("event_id"))(
+		func(context.Context, models.ModelExample) error {
+			calls++
+			if failNext {
+				failNext = false
+				return errors.New("boom")
+			}
+			return nil
+		})
+
+	ctx := ContextWithMessage(context.Background(), Message{Message: kafka.Message{
+		Headers: []kafka.Header{{Key: "event_id", Value: []byte("evt-1")}},
+	}})
+
+	// un échec n'enregistre pas la clé : le message est retraité
+	assert.Error(t, handler(ctx, models.ModelExample{}))
+	assert.NoError(t, handler(ctx, models.ModelExample{}))
+	assert.NoError(t, handler(ctx, models.ModelExample{}))
+	assert.Equal(t, 2, calls)
+
+	// sans header, pas de déduplication
+	assert.NoError(t, handler(context.Background(), models.ModelExample{}))
+	assert.Equal(t, 3, calls)
+}
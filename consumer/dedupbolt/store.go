@@ -0,0 +1,110 @@
+// Package dedupbolt fournit un consumer.DedupStore persistant basé sur bbolt,
+// pour conserver les clés d'idempotence entre deux redémarrages du service.
+package dedupbolt
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var bucketName = []byte("dedup")
+
+// Store enregistre les clés d'idempotence dans un fichier bbolt
+type Store struct {
+	db  *bolt.DB
+	ttl time.Duration
+}
+
+// Open ouvre (ou crée) le fichier `path` ; ttl <= 0 désactive l'expiration
+func Open(path string, ttl time.Duration) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("erreur d'ouverture de %s : %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("erreur de création du bucket : %w", err)
+	}
+
+	return &Store{db: db, ttl: ttl}, nil
+}
+
+// Seen indique si la clé est présente et non expirée
+func (s *Store) Seen(_ context.Context, key string) (bool, error) {
+	var seen bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketName).Get([]byte(key))
+		if v == nil {
+			return nil
+		}
+		seen = !expired(v, time.Now())
+		return nil
+	})
+	return seen, err
+}
+
+// Mark enregistre la clé avec sa date d'expiration
+func (s *Store) Mark(_ context.Context, key string) error {
+	var expiresAt int64
+	if s.ttl > 0 {
+		expiresAt = time.Now().Add(s.ttl).UnixNano()
+	}
+
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, uint64(expiresAt))
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Put([]byte(key), v)
+	})
+}
+
+// Purge supprime les clés expirées et retourne leur nombre
+func (s *Store) Purge(_ context.Context) (int, error) {
+	now := time.Now()
+	var purged int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketName)
+		// Supprimer pendant le parcours du curseur sauterait la clé suivante
+		var keys [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			if expired(v, now) {
+				keys = append(keys, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		purged = len(keys)
+		return nil
+	})
+	return purged, err
+}
+
+// Close ferme le fichier bbolt
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// expired décode la date d'expiration stockée (0 : jamais)
+func expired(v []byte, now time.Time) bool {
+	if len(v) != 8 {
+		return true
+	}
+	expiresAt := int64(binary.BigEndian.Uint64(v))
+	return expiresAt != 0 && now.UnixNano() > expiresAt
+}
@@ -0,0 +1,82 @@
+package dedupbolt
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openStore(t *testing.T, ttl time.Duration) *Store {
+	store, err := Open(filepath.Join(t.TempDir(), "dedup.db"), ttl)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestStore_SeenAndMark(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "dedup.db")
+	store, err := Open(path, 0)
+	require.NoError(t, err)
+
+	seen, err := store.Seen(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, seen)
+
+	require.NoError(t, store.Mark(ctx, "a"))
+	seen, err = store.Seen(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, seen)
+
+	// les clés survivent à la réouverture du fichier
+	require.NoError(t, store.Close())
+	store, err = Open(path, 0)
+	require.NoError(t, err)
+	defer store.Close()
+	seen, err = store.Seen(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, seen)
+}
+
+func TestStore_Expires(t *testing.T) {
+	ctx := context.Background()
+	store := openStore(t, 10*time.Millisecond)
+
+	require.NoError(t, store.Mark(ctx, "a"))
+	time.Sleep(20 * time.Millisecond)
+
+	seen, err := store.Seen(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, seen)
+}
+
+func TestStore_Purge(t *testing.T) {
+	ctx := context.Background()
+	store := openStore(t, 10*time.Millisecond)
+
+	// suite de clés expirées consécutives, puis des clés encore valides
+	for i := 0; i < 10; i++ {
+		require.NoError(t, store.Mark(ctx, fmt.Sprintf("expired-%02d", i)))
+	}
+	time.Sleep(20 * time.Millisecond)
+	store.ttl = time.Hour
+	require.NoError(t, store.Mark(ctx, "valid-a"))
+	require.NoError(t, store.Mark(ctx, "valid-b"))
+
+	purged, err := store.Purge(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 10, purged)
+
+	purged, err = store.Purge(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	seen, err := store.Seen(ctx, "valid-a")
+	require.NoError(t, err)
+	assert.True(t, seen)
+}
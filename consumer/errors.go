@@ -0,0 +1,59 @@
+package consumer
+
+import "errors"
+
+// Erreurs sentinelles, à tester avec errors.Is
+var (
+	// ErrPermanent : le message ne sera jamais traité avec succès, inutile de réessayer
+	ErrPermanent = errors.New("erreur permanente")
+	// ErrRetryable : l'échec est temporaire (réseau, dépendance indisponible…)
+	ErrRetryable = errors.New("erreur temporaire")
+	// ErrSkip : le handler ignore volontairement le message
+	ErrSkip = errors.New("message ignoré")
+	// ErrPoisonMessage : le message ne peut pas être décodé avec le schéma Avro attendu
+	ErrPoisonMessage = errors.New("message illisible")
+)
+
+// Permanent signale que l'erreur ne doit pas être réessayée
+func Permanent(err error) error {
+	return classify(ErrPermanent, err)
+}
+
+// Retryable signale que le traitement peut être réessayé (voir Config.MaxRetries)
+func Retryable(err error) error {
+	return classify(ErrRetryable, err)
+}
+
+// Skip signale que le message est ignoré : il est commité sans être compté en erreur.
+// `err` (éventuellement nil) précise la raison.
+func Skip(err error) error {
+	return classify(ErrSkip, err)
+}
+
+// classifiedError associe une classe (sentinelle) à l'erreur d'origine ;
+// errors.Is fonctionne à la fois sur la classe et sur l'erreur d'origine.
+type classifiedError struct {
+	class error
+	err   error
+}
+
+func classify(class, err error) error {
+	if err == nil && class != ErrSkip {
+		return nil
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+func (e *classifiedError) Error() string {
+	if e.err == nil {
+		return e.class.Error()
+	}
+	return e.class.Error() + " : " + e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	if e.err == nil {
+		return []error{e.class}
+	}
+	return []error{e.class, e.err}
+}
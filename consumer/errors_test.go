@@ -0,0 +1,90 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/metrics"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/hamba/avro"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConsumer(cfg Config) *Consumer[models.ModelExample] {
+	return &Consumer[models.ModelExample]{
+		cfg:     cfg,
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		metrics: metrics.Nop{},
+	}
+}
+
+func encodeExample(t *testing.T, event models.ModelExample) []byte {
+	value, err := avro.Marshal(avro.MustParse(event.GetSchema()), event)
+	require.NoError(t, err)
+	return value
+}
+
+func TestClassifiedErrors(t *testing.T) {
+	cause := errors.New("base indisponible")
+
+	err := Retryable(cause)
+	assert.ErrorIs(t, err, ErrRetryable)
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, ErrPermanent)
+	assert.Equal(t, "erreur temporaire : base indisponible", err.Error())
+
+	assert.ErrorIs(t, Permanent(cause), ErrPermanent)
+	assert.ErrorIs(t, Skip(nil), ErrSkip)
+	assert.NoError(t, Permanent(nil))
+}
+
+func TestProcess_RetriesRetryableErrors(t *testing.T) {
+	c := newTestConsumer(Config{MaxRetries: 2, RetryBackoff: time.Millisecond})
+	msg := kafka.Message{Topic: "orders", Value: encodeExample(t, models.ModelExample{ID: "42"})}
+
+	var calls int
+	commit := c.process(context.Background(), msg, func(_ context.Context, event models.ModelExample) error {
+		calls++
+		assert.Equal(t, "42", event.ID)
+		return Retryable(errors.New("timeout"))
+	})
+	assert.True(t, commit)
+	assert.Equal(t, 3, calls)
+
+	// une erreur permanente n'est jamais réessayée
+	calls = 0
+	c.process(context.Background(), msg, func(context.Context, models.ModelExample) error {
+		calls++
+		return Permanent(errors.New("refusé"))
+	})
+	assert.Equal(t, 1, calls)
+}
+
+func TestProcess_PoisonMessage(t *testing.T) {
+	var reported error
+	c := newTestConsumer(Config{})
+	c.hooks = Hooks{OnError: func(ctx context.Context, err error) {
+		reported = err
+		msg, ok := MessageFromContext(ctx)
+		require.True(t, ok)
+		assert.Equal(t, int64(7), msg.Offset)
+	}}
+
+	var called bool
+	commit := c.process(context.Background(), kafka.Message{Topic: "orders", Offset: 7, Value: []byte{0xff}},
+		func(context.Context, models.ModelExample) error {
+			called = true
+			return nil
+		})
+
+	// sans topic de quarantaine, le message illisible est journalisé puis commité
+	assert.True(t, commit)
+	assert.False(t, called)
+	assert.ErrorIs(t, reported, ErrPoisonMessage)
+}
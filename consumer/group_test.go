@@ -0,0 +1,258 @@
+package consumer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/metrics"
+	"github.com/hamba/avro"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReader simule le Reader d'une partition : les messages sont lus sur `messages`
+type fakeReader struct {
+	messages chan kafka.Message
+
+	mu     sync.Mutex
+	offset int64
+	closed bool
+}
+
+func newFakeReader() *fakeReader {
+	return &fakeReader{messages: make(chan kafka.Message, 10)}
+}
+
+func (r *fakeReader) SetOffset(offset int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.offset = offset
+	return nil
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	select {
+	case msg := <-r.messages:
+		return msg, nil
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	}
+}
+
+func (r *fakeReader) Stats() kafka.ReaderStats { return kafka.ReaderStats{} }
+
+func (r *fakeReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return nil
+}
+
+func (r *fakeReader) isClosed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closed
+}
+
+// fakeGeneration enregistre les offsets commités
+type fakeGeneration struct {
+	mu      sync.Mutex
+	commits map[TopicPartition]int64
+}
+
+func (g *fakeGeneration) CommitOffsets(offsets map[string]map[int]int64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.commits == nil {
+		g.commits = make(map[TopicPartition]int64)
+	}
+	for topic, partitions := range offsets {
+		for partition, offset := range partitions {
+			g.commits[TopicPartition{Topic: topic, Partition: partition}] = offset
+		}
+	}
+	return nil
+}
+
+func (g *fakeGeneration) committed(tp TopicPartition) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.commits[tp]
+}
+
+// groupHarness fait tourner un worker d'un Consumer dont les partitions sont
+// lues par des fakeReader et commitées dans une fakeGeneration
+type groupHarness struct {
+	c   *Consumer[keyedOrder]
+	gen *fakeGeneration
+
+	mu       sync.Mutex
+	readers  map[TopicPartition]*fakeReader
+	assigned []TopicPartition
+	revoked  []TopicPartition
+	onRevoke func() // appelé par OnPartitionsRevoked
+}
+
+func newGroupHarness(t *testing.T, handle Handler[keyedOrder]) (*groupHarness, context.Context, context.Context) {
+	h := &groupHarness{readers: make(map[TopicPartition]*fakeReader), gen: &fakeGeneration{}}
+	h.c = &Consumer[keyedOrder]{
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		metrics: metrics.Nop{},
+		readers: make(map[TopicPartition]partitionReader),
+		jobs:    make(chan job),
+		hooks: Hooks{
+			OnPartitionsAssigned: func(_ context.Context, partitions []TopicPartition) {
+				h.mu.Lock()
+				defer h.mu.Unlock()
+				h.assigned = partitions
+			},
+			OnPartitionsRevoked: func(_ context.Context, partitions []TopicPartition) {
+				h.mu.Lock()
+				defer h.mu.Unlock()
+				h.revoked = partitions
+				if h.onRevoke != nil {
+					h.onRevoke()
+				}
+			},
+		},
+	}
+	h.c.newReader = func(topic string, partition int) partitionReader {
+		r := newFakeReader()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.readers[TopicPartition{Topic: topic, Partition: partition}] = r
+		return r
+	}
+
+	fetchCtx, stopFetch := context.WithCancel(context.Background())
+	handleCtx, abort := context.WithCancel(context.Background())
+	h.c.stopFetch, h.c.abort = stopFetch, abort
+	h.c.wg.Add(1)
+	go h.c.worker(fetchCtx, handleCtx, handle)
+	t.Cleanup(func() {
+		stopFetch()
+		abort()
+		h.c.wg.Wait()
+	})
+	return h, fetchCtx, handleCtx
+}
+
+func (h *groupHarness) reader(t *testing.T, tp TopicPartition) *fakeReader {
+	var r *fakeReader
+	require.Eventually(t, func() bool {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		r = h.readers[tp]
+		return r != nil
+	}, time.Second, time.Millisecond)
+	return r
+}
+
+func orderMessage(t *testing.T, partition int, offset int64, id string) kafka.Message {
+	order := keyedOrder{ID: id, Amount: 1}
+	value, err := avro.Marshal(avro.MustParse(order.GetSchema()), order)
+	require.NoError(t, err)
+	return kafka.Message{Topic: "orders", Partition: partition, Offset: offset, Value: value}
+}
+
+// consume lance consumeGeneration pour `assignments` ; la génération se termine
+// à l'appel de la fonction retournée, qui attend la révocation
+func (h *groupHarness) consume(t *testing.T, fetchCtx, handleCtx context.Context,
+	assignments map[string][]kafka.PartitionAssignment) (end func()) {
+	genCtx, endGen := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.c.consumeGeneration(fetchCtx, genCtx, handleCtx, 3, assignments, h.gen)
+	}()
+	return func() {
+		endGen()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Error("la génération ne s'est pas terminée")
+		}
+	}
+}
+
+func TestConsumeGeneration_AssignCommitRevoke(t *testing.T) {
+	handled := make(chan string, 10)
+	h, fetchCtx, handleCtx := newGroupHarness(t, func(_ context.Context, o keyedOrder) error {
+		handled <- o.ID
+		return nil
+	})
+	p0, p1 := TopicPartition{Topic: "orders", Partition: 0}, TopicPartition{Topic: "orders", Partition: 1}
+	end := h.consume(t, fetchCtx, handleCtx, map[string][]kafka.PartitionAssignment{
+		"orders": {{ID: 1, Offset: 0}, {ID: 0, Offset: 5}},
+	})
+
+	// les Readers démarrent à l'offset commité de chaque partition
+	r0, r1 := h.reader(t, p0), h.reader(t, p1)
+	r0.mu.Lock()
+	assert.Equal(t, int64(5), r0.offset)
+	r0.mu.Unlock()
+	h.mu.Lock()
+	assert.Equal(t, []TopicPartition{p0, p1}, h.assigned)
+	h.mu.Unlock()
+	assert.True(t, h.c.Health().GroupMember)
+
+	// un message traité est commité (offset suivant)
+	r0.messages <- orderMessage(t, 0, 5, "a")
+	r0.messages <- orderMessage(t, 0, 6, "b")
+	assert.Equal(t, "a", <-handled)
+	assert.Equal(t, "b", <-handled)
+	require.Eventually(t, func() bool { return h.gen.committed(p0) == 7 }, time.Second, time.Millisecond)
+
+	// fin de génération : Readers arrêtés, partitions révoquées
+	end()
+	assert.True(t, r0.isClosed())
+	assert.True(t, r1.isClosed())
+	h.mu.Lock()
+	assert.Equal(t, []TopicPartition{p0, p1}, h.revoked)
+	h.mu.Unlock()
+	h.c.readersMu.Lock()
+	assert.Empty(t, h.c.readers)
+	h.c.readersMu.Unlock()
+	assert.Zero(t, h.gen.committed(p1), "aucun message lu sur la partition 1")
+	assert.False(t, h.c.Health().GroupMember)
+}
+
+// Un rééquilibrage attend les messages en cours et committe leur offset avant
+// de notifier la révocation
+func TestConsumeGeneration_RevocationWaitsForInFlight(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	h, fetchCtx, handleCtx := newGroupHarness(t, func(context.Context, keyedOrder) error {
+		close(started)
+		<-release
+		return nil
+	})
+	p0 := TopicPartition{Topic: "orders", Partition: 0}
+	var committedAtRevoke int64
+	h.onRevoke = func() { committedAtRevoke = h.gen.committed(p0) }
+	end := h.consume(t, fetchCtx, handleCtx, map[string][]kafka.PartitionAssignment{
+		"orders": {{ID: 0, Offset: 10}},
+	})
+
+	h.reader(t, p0).messages <- orderMessage(t, 0, 10, "a")
+	<-started
+
+	revoked := make(chan struct{})
+	go func() {
+		end()
+		close(revoked)
+	}()
+	select {
+	case <-revoked:
+		t.Fatal("révocation notifiée avant la fin du handler")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-revoked
+	assert.Equal(t, int64(11), committedAtRevoke)
+}
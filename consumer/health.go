@@ -0,0 +1,124 @@
+package consumer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/health"
+)
+
+// status suit l'activité du Consumer pour Health()
+type status struct {
+	mu sync.Mutex
+
+	running              bool
+	groupMember          bool
+	lastFetchAt          time.Time
+	lastHandledAt        time.Time
+	lastReaderErrorAt    time.Time
+	lastBrokerOKAt       time.Time // Dernier échange réussi avec le broker (lecture, commit, groupe)
+	outsideBusinessHours bool
+	errorStreak          int
+	lastError            string
+}
+
+func (s *status) setRunning(running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = running
+}
+
+func (s *status) setGroupMember(member bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groupMember = member
+}
+
+// readerErrorTTL : une erreur Kafka isolée n'indique plus une perte de connexion
+// passé ce délai (kafka-go la signale de nouveau tant que la connexion échoue)
+const readerErrorTTL = time.Minute
+
+func (s *status) fetched() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastFetchAt = time.Now()
+	s.lastBrokerOKAt = s.lastFetchAt
+}
+
+// brokerReached enregistre un échange réussi avec le broker hors lecture (commit, groupe)
+func (s *status) brokerReached() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastBrokerOKAt = time.Now()
+}
+
+// readerFailed enregistre une erreur interne du kafka.Reader (connexion, groupe…)
+func (s *status) readerFailed(msg string, args ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastReaderErrorAt = time.Now()
+	s.lastError = fmt.Sprintf(msg, args...)
+}
+
+func (s *status) handled() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastHandledAt = time.Now()
+	s.errorStreak = 0
+}
+
+func (s *status) failed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errorStreak++
+	s.lastError = err.Error()
+}
+
+func (s *status) setOutsideBusinessHours(outside bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outsideBusinessHours = outside
+}
+
+// Health retourne l'état courant du Consumer.
+// La connexion au broker est considérée perdue après une erreur Kafka, jusqu'au
+// prochain échange réussi (lecture, commit, groupe) ou pendant readerErrorTTL
+// au plus ; le Consumer n'est prêt qu'une fois membre du groupe.
+func (c *Consumer[T]) Health() health.Report {
+	s := &c.status
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	connected := s.lastReaderErrorAt.IsZero() || s.lastBrokerOKAt.After(s.lastReaderErrorAt) ||
+		time.Since(s.lastReaderErrorAt) > readerErrorTTL
+
+	breakerState := c.breaker.State()
+	var circuitState string
+	if c.breaker != nil {
+		circuitState = breakerState.String()
+	}
+
+	return health.Report{
+		Name:                 "consumer:" + c.cfg.Topic,
+		Live:                 s.running,
+		Ready:                s.running && connected && s.groupMember,
+		BrokerConnected:      connected,
+		GroupMember:          s.groupMember,
+		LastFetchAt:          timePtr(s.lastFetchAt),
+		LastHandledAt:        timePtr(s.lastHandledAt),
+		Paused:               s.outsideBusinessHours || breakerState != BreakerClosed,
+		OutsideBusinessHours: s.outsideBusinessHours,
+		CircuitState:         circuitState,
+		ErrorStreak:          s.errorStreak,
+		LastError:            s.lastError,
+	}
+}
+
+// timePtr retourne nil pour une date nulle (omise du JSON)
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
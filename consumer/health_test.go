@@ -0,0 +1,31 @@
+package consumer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth_BrokerConnection(t *testing.T) {
+	c := &Consumer[keyedOrder]{cfg: Config{Topic: "orders"}}
+	c.status.setRunning(true)
+	c.status.setGroupMember(true)
+	assert.True(t, c.Health().BrokerConnected)
+
+	c.status.readerFailed("connexion perdue")
+	assert.False(t, c.Health().BrokerConnected)
+	assert.False(t, c.Health().Ready)
+
+	// un commit (ou une lecture) réussi rétablit la connexion, même sans message
+	c.status.brokerReached()
+	assert.True(t, c.Health().BrokerConnected)
+
+	// une erreur isolée expire sur un topic inactif
+	c.status.readerFailed("connexion perdue")
+	c.status.mu.Lock()
+	c.status.lastReaderErrorAt = time.Now().Add(-2 * readerErrorTTL)
+	c.status.mu.Unlock()
+	assert.True(t, c.Health().BrokerConnected)
+	assert.True(t, c.Health().Ready)
+}
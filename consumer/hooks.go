@@ -0,0 +1,69 @@
+package consumer
+
+import (
+	"context"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+)
+
+// TopicPartition identifie une partition assignée au Consumer
+type TopicPartition struct {
+	Topic     string
+	Partition int
+}
+
+// Hooks regroupe les callbacks optionnels du cycle de vie du Consumer.
+// Ils sont appelés de manière synchrone : un hook lent retarde le rééquilibrage.
+type Hooks struct {
+	// OnPartitionsAssigned est appelé au début de chaque génération du groupe,
+	// avant la lecture du premier message des partitions assignées
+	OnPartitionsAssigned func(ctx context.Context, partitions []TopicPartition)
+	// OnPartitionsRevoked est appelé en fin de génération, une fois les messages
+	// en cours traités et leurs offsets commités : c'est le moment de vider les
+	// buffers par partition
+	OnPartitionsRevoked func(ctx context.Context, partitions []TopicPartition)
+	// OnStart est appelé par Start, avant la lecture du premier message
+	OnStart func(ctx context.Context)
+	// OnStop est appelé par Close, une fois la consommation entièrement arrêtée
+	OnStop func(ctx context.Context)
+	// OnError est appelé pour chaque erreur (groupe, lecture, commit, décodage,
+	// handler) ; pour les erreurs liées à un message, ctx porte le Message
+	OnError func(ctx context.Context, err error)
+}
+
+// WithHooks enregistre les callbacks du cycle de vie (les champs nil sont ignorés)
+func WithHooks[T models.AvroEvent](hooks Hooks) Option[T] {
+	return func(c *Consumer[T]) {
+		c.hooks = hooks
+	}
+}
+
+func (h Hooks) partitionsAssigned(ctx context.Context, partitions []TopicPartition) {
+	if h.OnPartitionsAssigned != nil {
+		h.OnPartitionsAssigned(ctx, partitions)
+	}
+}
+
+func (h Hooks) partitionsRevoked(ctx context.Context, partitions []TopicPartition) {
+	if h.OnPartitionsRevoked != nil {
+		h.OnPartitionsRevoked(ctx, partitions)
+	}
+}
+
+func (h Hooks) start(ctx context.Context) {
+	if h.OnStart != nil {
+		h.OnStart(ctx)
+	}
+}
+
+func (h Hooks) stop(ctx context.Context) {
+	if h.OnStop != nil {
+		h.OnStop(ctx)
+	}
+}
+
+func (h Hooks) error(ctx context.Context, err error) {
+	if h.OnError != nil {
+		h.OnError(ctx, err)
+	}
+}
@@ -0,0 +1,63 @@
+package consumer
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/segmentio/kafka-go"
+)
+
+// Handler traite un événement décodé
+type Handler[T models.AvroEvent] func(ctx context.Context, event T) error
+
+// Message enveloppe le message Kafka brut en cours de traitement
+type Message struct {
+	kafka.Message
+	GroupID    string // Groupe de consommateurs ayant lu le message
+	DecodedKey any    // Clé décodée en Avro si l'événement implémente models.KeyedEvent (nil sinon)
+	Deleted    bool   // Tombstone (valeur nulle) : l'événement est vide, seule la clé identifie l'entité supprimée
+}
+
+// Header retourne la valeur du header `name` (chaîne vide si absent)
+func (m Message) Header(name string) string {
+	for _, h := range m.Headers {
+		if h.Key == name {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+type messageKey struct{}
+
+// ContextWithMessage attache le message en cours au contexte du handler
+// (utile aussi pour tester un handler ou un middleware)
+func ContextWithMessage(ctx context.Context, msg Message) context.Context {
+	return context.WithValue(ctx, messageKey{}, msg)
+}
+
+// MessageFromContext retourne le message Kafka en cours de traitement
+// (false si le contexte ne provient pas d'un Consumer)
+func MessageFromContext(ctx context.Context) (Message, bool) {
+	msg, ok := ctx.Value(messageKey{}).(Message)
+	return msg, ok
+}
+
+// KeyFromContext retourne la clé Avro décodée du message en cours, du type K
+// retourné par Key() (false si le message n'a pas de clé de ce type)
+func KeyFromContext[K any](ctx context.Context) (K, bool) {
+	msg, _ := MessageFromContext(ctx)
+	key, ok := msg.DecodedKey.(K)
+	return key, ok
+}
+
+// messageAttrs retourne les champs de log identifiant un message
+func messageAttrs(msg kafka.Message) []any {
+	return []any{
+		slog.String("topic", msg.Topic),
+		slog.Int("partition", msg.Partition),
+		slog.Int64("offset", msg.Offset),
+		slog.String("key", string(msg.Key)),
+	}
+}
@@ -0,0 +1,76 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+)
+
+// Middleware enveloppe un Handler pour y ajouter un comportement transverse
+type Middleware[T models.AvroEvent] func(Handler[T]) Handler[T]
+
+// ErrHandlerPanic est retournée par Recover quand le handler panique
+var ErrHandlerPanic = errors.New("panic dans le handler")
+
+// Chain compose les middlewares : le premier de la liste est le plus externe
+func Chain[T models.AvroEvent](handler Handler[T], middlewares ...Middleware[T]) Handler[T] {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// Recover transforme une panique du handler en erreur (ErrHandlerPanic)
+// au lieu de faire tomber le worker et tout le processus
+func Recover[T models.AvroEvent]() Middleware[T] {
+	return func(next Handler[T]) Handler[T] {
+		return func(ctx context.Context, event T) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("%w : %v\n%s", ErrHandlerPanic, r, debug.Stack())
+				}
+			}()
+			return next(ctx, event)
+		}
+	}
+}
+
+// Timeout borne la durée de traitement d'un message : le contexte transmis au
+// handler est annulé après `d` (le handler doit respecter ctx.Done())
+func Timeout[T models.AvroEvent](d time.Duration) Middleware[T] {
+	return func(next Handler[T]) Handler[T] {
+		return func(ctx context.Context, event T) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return next(ctx, event)
+		}
+	}
+}
+
+// Logging journalise chaque traitement avec le topic, la partition, l'offset,
+// la clé et la durée (niveau Debug en cas de succès, Error en cas d'échec)
+func Logging[T models.AvroEvent](logger *slog.Logger) Middleware[T] {
+	return func(next Handler[T]) Handler[T] {
+		return func(ctx context.Context, event T) error {
+			start := time.Now()
+			err := next(ctx, event)
+
+			attrs := []any{slog.Duration("duration", time.Since(start))}
+			if msg, ok := MessageFromContext(ctx); ok {
+				attrs = append(attrs, messageAttrs(msg.Message)...)
+			}
+
+			if err != nil {
+				logger.ErrorContext(ctx, "échec du traitement du message", append(attrs, slog.Any("error", err))...)
+				return err
+			}
+			logger.DebugContext(ctx, "message traité", attrs...)
+			return nil
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package consumer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain_Order(t *testing.T) {
+	var calls []string
+	mw := func(name string) Middleware[models.ModelExample] {
+		return func(next Handler[models.ModelExample]) Handler[models.ModelExample] {
+			return func(ctx context.Context, event models.ModelExample) error {
+				calls = append(calls, name)
+				return next(ctx, event)
+			}
+		}
+	}
+
+	handler := Chain(func(context.Context, models.ModelExample) error {
+		calls = append(calls, "handler")
+		return nil
+	}, mw("first"), mw("second"))
+
+	require.NoError(t, handler(context.Background(), models.ModelExample{}))
+	assert.Equal(t, []string{"first", "second", "handler"}, calls)
+}
+
+func TestRecover_ConvertsPanicToError(t *testing.T) {
+	handler := Recover[models.ModelExample]()(func(context.Context, models.ModelExample) error {
+		panic("boom")
+	})
+
+	err := handler(context.Background(), models.ModelExample{})
+	assert.ErrorIs(t, err, ErrHandlerPanic)
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestTimeout_CancelsHandlerContext(t *testing.T) {
+	handler := Timeout[models.ModelExample](10 * time.Millisecond)(func(ctx context.Context, _ models.ModelExample) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	assert.ErrorIs(t, handler(context.Background(), models.ModelExample{}), context.DeadlineExceeded)
+}
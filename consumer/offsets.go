@@ -0,0 +1,58 @@
+package consumer
+
+import "sync"
+
+// offsetTracker calcule l'offset commitable d'une partition lorsque plusieurs
+// workers traitent ses messages en parallèle : on ne commite jamais au-delà du
+// plus ancien message encore en cours, pour ne perdre aucun message à l'arrêt.
+type offsetTracker struct {
+	mu        sync.Mutex
+	pending   map[int64]struct{} // Offsets lus mais pas encore traités
+	next      int64              // Offset suivant le dernier message lu
+	committed int64              // Dernier offset commité (-1 : aucun)
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{pending: make(map[int64]struct{}), next: -1, committed: -1}
+}
+
+// fetched enregistre un message lu
+func (t *offsetTracker) fetched(offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[offset] = struct{}{}
+	if offset+1 > t.next {
+		t.next = offset + 1
+	}
+}
+
+// done retire un message traité (ou abandonné) des messages en cours
+func (t *offsetTracker) done(offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, offset)
+}
+
+// commit appelle `commitFn` avec l'offset commitable s'il a progressé.
+// Le verrou est conservé pendant l'appel pour que deux commits concurrents
+// ne fassent jamais reculer l'offset.
+func (t *offsetTracker) commit(commitFn func(offset int64) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	offset := t.next
+	for pending := range t.pending {
+		if pending < offset {
+			offset = pending
+		}
+	}
+	if offset <= t.committed {
+		return nil
+	}
+
+	if err := commitFn(offset); err != nil {
+		return err
+	}
+	t.committed = offset
+	return nil
+}
@@ -0,0 +1,49 @@
+package consumer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffsetTracker_NeverCommitsPastPendingMessage(t *testing.T) {
+	tracker := newOffsetTracker()
+	var commits []int64
+	commitFn := func(offset int64) error {
+		commits = append(commits, offset)
+		return nil
+	}
+
+	tracker.fetched(10)
+	tracker.fetched(11)
+	tracker.fetched(12)
+
+	// 11 et 12 sont traités avant 10 : rien n'est commitable au-delà de 10
+	tracker.done(12)
+	tracker.done(11)
+	require.NoError(t, tracker.commit(commitFn))
+
+	tracker.done(10)
+	require.NoError(t, tracker.commit(commitFn))
+	// pas de nouveau commit si l'offset n'a pas progressé
+	require.NoError(t, tracker.commit(commitFn))
+
+	assert.Equal(t, []int64{10, 13}, commits)
+}
+
+func TestOffsetTracker_RetriesFailedCommit(t *testing.T) {
+	tracker := newOffsetTracker()
+	tracker.fetched(5)
+	tracker.done(5)
+
+	assert.Error(t, tracker.commit(func(int64) error { return errors.New("boom") }))
+
+	var committed int64
+	require.NoError(t, tracker.commit(func(offset int64) error {
+		committed = offset
+		return nil
+	}))
+	assert.Equal(t, int64(6), committed)
+}
@@ -0,0 +1,56 @@
+package consumer
+
+import (
+	"log/slog"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/metrics"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/registry"
+)
+
+// Option personnalise un Consumer à sa création
+type Option[T models.AvroEvent] func(*Consumer[T])
+
+// WithMiddleware ajoute des middlewares autour du handler passé à Start
+// (le premier middleware est le plus externe)
+func WithMiddleware[T models.AvroEvent](middlewares ...Middleware[T]) Option[T] {
+	return func(c *Consumer[T]) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithLogger remplace le logger par défaut (slog.Default()).
+// Le niveau de log se configure via le slog.Handler fourni ; aucun message
+// n'est journalisé au niveau Info pour chaque message consommé.
+func WithLogger[T models.AvroEvent](logger *slog.Logger) Option[T] {
+	return func(c *Consumer[T]) {
+		c.logger = logger
+	}
+}
+
+// WithMetrics transmet les mesures du Consumer au collecteur fourni
+func WithMetrics[T models.AvroEvent](collector metrics.Collector) Option[T] {
+	return func(c *Consumer[T]) {
+		c.metrics = collector
+	}
+}
+
+// WithSchemaRegistry décode les messages au format Confluent avec le schéma
+// d'écriture désigné par leur identifiant, après avoir vérifié qu'il appartient
+// au sujet calculé par `strategy` (TopicNameStrategy si nil) pour Config.Topic.
+// Prioritaire sur Config.SchemaRegistryURL.
+func WithSchemaRegistry[T models.AvroEvent](client *registry.Client, strategy registry.SubjectNameStrategy) Option[T] {
+	return func(c *Consumer[T]) {
+		c.serde = registry.NewSerde(client, strategy)
+	}
+}
+
+// WithTombstones transmet les tombstones (messages de valeur nulle des topics
+// compactés) au handler avec un événement vide et Message.Deleted ; la clé se lit
+// dans le Message ou, pour un models.KeyedEvent, avec KeyFromContext.
+// Sans cette option, les tombstones sont commitées sans appeler le handler.
+func WithTombstones[T models.AvroEvent]() Option[T] {
+	return func(c *Consumer[T]) {
+		c.tombstones = true
+	}
+}
@@ -0,0 +1,67 @@
+package consumer
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+// Headers ajoutés aux messages mis en quarantaine, en retry ou en dead letter
+// queue (en plus des headers d'origine)
+const (
+	HeaderOriginalTopic     = "original_topic"
+	HeaderOriginalPartition = "original_partition"
+	HeaderOriginalOffset    = "original_offset"
+	HeaderError             = "error"
+)
+
+// newWriter crée le writer des topics de quarantaine, de retry et de dead letter
+// queue (le topic est porté par chaque message), avec la même authentification
+// que le Consumer
+func (c *Consumer[T]) newWriter() *kafka.Writer {
+	transport := &kafka.Transport{}
+	if c.cfg.SASL {
+		transport.SASL = plain.Mechanism{
+			Username: c.cfg.Username,
+			Password: c.cfg.Password,
+		}
+		transport.TLS = &tls.Config{}
+	}
+
+	return &kafka.Writer{
+		Addr:         kafka.TCP(c.cfg.Brokers...),
+		Transport:    transport,
+		Balancer:     &kafka.LeastBytes{},
+		RequiredAcks: kafka.RequireAll,
+	}
+}
+
+// quarantine recopie le message brut (clé, valeur, headers) sur le topic de
+// quarantaine avec l'erreur de décodage. Sans topic de quarantaine, le message
+// est seulement journalisé. Retourne false si l'envoi échoue : le message n'est
+// alors pas commité et sera relu au prochain rééquilibrage.
+func (c *Consumer[T]) quarantine(ctx context.Context, msg kafka.Message, cause error) bool {
+	if c.cfg.QuarantineTopic == "" {
+		return true
+	}
+
+	err := c.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   c.cfg.QuarantineTopic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: withOriginHeaders(msg, cause),
+	})
+	if err != nil {
+		c.logger.Error("échec de la mise en quarantaine", append(messageAttrs(msg), slog.Any("error", err))...)
+		c.hooks.error(ctx, err)
+		return false
+	}
+
+	c.metrics.DLQSent(msg.Topic)
+	c.logger.Warn("message mis en quarantaine", append(messageAttrs(msg),
+		slog.String("quarantine_topic", c.cfg.QuarantineTopic))...)
+	return true
+}
@@ -0,0 +1,147 @@
+package consumer
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers posés sur les messages republiés dans les topics de retry
+const (
+	HeaderRetryAttempt = "retry_attempt" // Nombre d'échecs déjà subis par le message
+	HeaderRetryDueAt   = "retry_due_at"  // Date (RFC 3339) avant laquelle le message ne doit pas être retraité
+)
+
+// RetryTopic retourne le nom du topic de retry de `topic` pour le délai `delay`,
+// par exemple "orders.retry.10m"
+func RetryTopic(topic string, delay time.Duration) string {
+	return topic + ".retry." + formatDelay(delay)
+}
+
+// formatDelay écrit une durée sans unités nulles ("1h" plutôt que "1h0m0s")
+func formatDelay(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Attempt retourne le nombre d'échecs déjà subis par le message (0 à la première livraison)
+func (m Message) Attempt() int {
+	attempt, _ := strconv.Atoi(m.Header(HeaderRetryAttempt))
+	return attempt
+}
+
+// topics retourne les topics lus par le groupe : le topic principal et ses topics de retry
+func (c *Consumer[T]) topics() []string {
+	topics := []string{c.cfg.Topic}
+	for _, delay := range c.cfg.RetryTiers {
+		topics = append(topics, RetryTopic(c.cfg.Topic, delay))
+	}
+	return topics
+}
+
+// waitUntilDue bloque jusqu'à la date de retraitement d'un message lu sur un
+// topic de retry. Chaque topic ayant un délai fixe, les messages d'une partition
+// arrivent à échéance dans l'ordre : attendre le premier ne retarde pas les suivants.
+// Retourne false si le contexte est annulé pendant l'attente.
+func (c *Consumer[T]) waitUntilDue(ctx context.Context, msg kafka.Message) bool {
+	dueAt, err := time.Parse(time.RFC3339Nano, Message{Message: msg}.Header(HeaderRetryDueAt))
+	if err != nil {
+		return true
+	}
+
+	delay := time.Until(dueAt)
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// forward republie un message en échec sur le topic de retry suivant, ou sur la
+// dead letter queue une fois les topics de retry épuisés (ou si l'erreur n'est pas
+// Retryable). Sans topic de destination, le message est seulement journalisé.
+// Retourne false si la republication échoue : le message n'est alors pas commité.
+func (c *Consumer[T]) forward(ctx context.Context, msg kafka.Message, cause error, retryable bool) bool {
+	attempt := Message{Message: msg}.Attempt()
+
+	var topic string
+	headers := withOriginHeaders(msg, cause)
+	switch {
+	case retryable && attempt < len(c.cfg.RetryTiers):
+		delay := c.cfg.RetryTiers[attempt]
+		topic = RetryTopic(c.cfg.Topic, delay)
+		headers = setHeader(headers, HeaderRetryAttempt, strconv.Itoa(attempt+1))
+		headers = setHeader(headers, HeaderRetryDueAt, time.Now().Add(delay).UTC().Format(time.RFC3339Nano))
+	case c.cfg.DLQTopic != "":
+		topic = c.cfg.DLQTopic
+	default:
+		return true
+	}
+
+	err := c.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	})
+	if err != nil {
+		c.logger.Error("échec de la republication du message", append(messageAttrs(msg),
+			slog.String("to", topic), slog.Any("error", err))...)
+		c.hooks.error(ctx, err)
+		return false
+	}
+
+	if topic == c.cfg.DLQTopic {
+		c.metrics.DLQSent(c.cfg.Topic)
+		c.logger.Warn("message envoyé en dead letter queue", append(messageAttrs(msg),
+			slog.String("dlq_topic", topic), slog.Int("attempt", attempt+1))...)
+	} else {
+		c.metrics.Retry(c.cfg.Topic)
+		c.logger.Info("message republié pour une nouvelle tentative", append(messageAttrs(msg),
+			slog.String("retry_topic", topic), slog.Int("attempt", attempt+1))...)
+	}
+	return true
+}
+
+// withOriginHeaders copie les headers du message et y ajoute sa provenance ;
+// la provenance d'un message déjà republié est conservée
+func withOriginHeaders(msg kafka.Message, cause error) []kafka.Header {
+	headers := slices.Clone(msg.Headers)
+	if (Message{Message: msg}).Header(HeaderOriginalTopic) == "" {
+		headers = append(headers,
+			kafka.Header{Key: HeaderOriginalTopic, Value: []byte(msg.Topic)},
+			kafka.Header{Key: HeaderOriginalPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+			kafka.Header{Key: HeaderOriginalOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		)
+	}
+	return setHeader(headers, HeaderError, cause.Error())
+}
+
+// setHeader remplace ou ajoute le header `key`
+func setHeader(headers []kafka.Header, key, value string) []kafka.Header {
+	for i, h := range headers {
+		if h.Key == key {
+			headers[i].Value = []byte(value)
+			return headers
+		}
+	}
+	return append(headers, kafka.Header{Key: key, Value: []byte(value)})
+}
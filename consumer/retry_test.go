@@ -0,0 +1,146 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryTopic(t *testing.T) {
+	assert.Equal(t, "orders.retry.1m", RetryTopic("orders", time.Minute))
+	assert.Equal(t, "orders.retry.10m", RetryTopic("orders", 10*time.Minute))
+	assert.Equal(t, "orders.retry.1h", RetryTopic("orders", time.Hour))
+	assert.Equal(t, "orders.retry.1h30m", RetryTopic("orders", 90*time.Minute))
+	assert.Equal(t, "orders.retry.30s", RetryTopic("orders", 30*time.Second))
+}
+
+func TestWithOriginHeaders_KeepsFirstOrigin(t *testing.T) {
+	first := kafka.Message{Topic: "orders", Partition: 2, Offset: 40}
+	headers := withOriginHeaders(first, errors.New("boom"))
+
+	// le message republié échoue à nouveau depuis le topic de retry
+	retried := kafka.Message{Topic: "orders.retry.1m", Partition: 0, Offset: 3, Headers: headers}
+	msg := Message{Message: kafka.Message{Headers: withOriginHeaders(retried, errors.New("encore"))}}
+
+	assert.Equal(t, "orders", msg.Header(HeaderOriginalTopic))
+	assert.Equal(t, "2", msg.Header(HeaderOriginalPartition))
+	assert.Equal(t, "40", msg.Header(HeaderOriginalOffset))
+	assert.Equal(t, "encore", msg.Header(HeaderError))
+}
+
+func TestWaitUntilDue(t *testing.T) {
+	c := newTestConsumer(Config{})
+	due := func(d time.Duration) kafka.Message {
+		return kafka.Message{Headers: []kafka.Header{
+			{Key: HeaderRetryDueAt, Value: []byte(time.Now().Add(d).UTC().Format(time.RFC3339Nano))},
+		}}
+	}
+
+	start := time.Now()
+	assert.True(t, c.waitUntilDue(context.Background(), due(30*time.Millisecond)))
+	assert.GreaterOrEqual(t, time.Since(start), 25*time.Millisecond)
+
+	// message du topic principal : pas d'attente
+	assert.True(t, c.waitUntilDue(context.Background(), kafka.Message{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, c.waitUntilDue(ctx, due(time.Hour)))
+	assert.Equal(t, 0, Message{}.Attempt())
+}
+
+// fakeWriter enregistre les messages republiés ; err simule un échec d'écriture
+type fakeWriter struct {
+	written []kafka.Message
+	err     error
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.written = append(w.written, msgs...)
+	return nil
+}
+
+func (w *fakeWriter) Close() error { return nil }
+
+func TestForward(t *testing.T) {
+	tiers := []time.Duration{time.Minute, 10 * time.Minute}
+	attempt := func(n string) []kafka.Header {
+		return []kafka.Header{{Key: HeaderRetryAttempt, Value: []byte(n)}}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		dlq       string
+		headers   []kafka.Header
+		retryable bool
+		writeErr  error
+		commit    bool
+		topic     string        // "" : aucun message republié
+		attempt   string        // header retry_attempt attendu
+		delay     time.Duration // échéance attendue (0 : pas de retry_due_at)
+	}{
+		{name: "premier tier", dlq: "orders.dlq", retryable: true, commit: true,
+			topic: "orders.retry.1m", attempt: "1", delay: time.Minute},
+		{name: "tier suivant", dlq: "orders.dlq", headers: attempt("1"), retryable: true, commit: true,
+			topic: "orders.retry.10m", attempt: "2", delay: 10 * time.Minute},
+		{name: "tiers épuisés", dlq: "orders.dlq", headers: attempt("2"), retryable: true, commit: true,
+			topic: "orders.dlq", attempt: "2"},
+		{name: "erreur permanente", dlq: "orders.dlq", retryable: false, commit: true,
+			topic: "orders.dlq"},
+		{name: "tiers épuisés sans DLQ", headers: attempt("2"), retryable: true, commit: true},
+		{name: "échec d'écriture", dlq: "orders.dlq", retryable: true, writeErr: errors.New("broker indisponible"),
+			commit: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &fakeWriter{err: tc.writeErr}
+			c := newTestConsumer(Config{Topic: "orders", RetryTiers: tiers, DLQTopic: tc.dlq})
+			c.writer = w
+			msg := kafka.Message{Topic: "orders", Partition: 1, Offset: 7, Key: []byte("k"), Value: []byte("v"), Headers: tc.headers}
+
+			assert.Equal(t, tc.commit, c.forward(context.Background(), msg, errors.New("boom"), tc.retryable))
+			if tc.topic == "" {
+				assert.Empty(t, w.written)
+				return
+			}
+			require.Len(t, w.written, 1)
+			out := Message{Message: w.written[0]}
+			assert.Equal(t, tc.topic, out.Topic)
+			assert.Equal(t, msg.Key, out.Key)
+			assert.Equal(t, msg.Value, out.Value)
+			assert.Equal(t, "orders", out.Header(HeaderOriginalTopic))
+			assert.Equal(t, "boom", out.Header(HeaderError))
+			assert.Equal(t, tc.attempt, out.Header(HeaderRetryAttempt))
+			if tc.delay == 0 {
+				assert.Empty(t, out.Header(HeaderRetryDueAt))
+				return
+			}
+			dueAt, err := time.Parse(time.RFC3339Nano, out.Header(HeaderRetryDueAt))
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(tc.delay), dueAt, 5*time.Second)
+		})
+	}
+}
+
+// Les tiers sont opt-in : seule une erreur Retryable y est republiée
+func TestProcess_OnlyRetryableErrorsUseRetryTiers(t *testing.T) {
+	w := &fakeWriter{}
+	c := newTestConsumer(Config{Topic: "orders", RetryTiers: []time.Duration{time.Minute}, DLQTopic: "orders.dlq"})
+	c.writer = w
+	msg := kafka.Message{Topic: "orders", Value: encodeExample(t, models.ModelExample{ID: "1"})}
+
+	for _, err := range []error{errors.New("boom"), Retryable(errors.New("boom"))} {
+		c.process(context.Background(), msg, func(context.Context, models.ModelExample) error { return err })
+	}
+	require.Len(t, w.written, 2)
+	assert.Equal(t, "orders.dlq", w.written[0].Topic)
+	assert.Equal(t, "orders.retry.1m", w.written[1].Topic)
+}
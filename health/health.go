@@ -0,0 +1,67 @@
+// Package health définit le rapport d'état des Consumers et Producers et un
+// handler net/http servant /healthz (liveness) et /readyz (readiness) en JSON.
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Report décrit l'état d'un Consumer ou d'un Producer
+type Report struct {
+	Name                 string     `json:"name"`
+	Live                 bool       `json:"live"`                             // Le composant tourne (liveness)
+	Ready                bool       `json:"ready"`                            // Le composant peut traiter des messages (readiness)
+	BrokerConnected      bool       `json:"broker_connected"`                 // Aucune erreur Kafka depuis le dernier succès
+	GroupMember          bool       `json:"group_member,omitempty"`           // Consumer : membre actif du groupe
+	LastFetchAt          *time.Time `json:"last_fetch_at,omitempty"`          // Consumer : dernier message lu
+	LastHandledAt        *time.Time `json:"last_handled_at,omitempty"`        // Consumer : dernier message traité avec succès
+	LastSentAt           *time.Time `json:"last_sent_at,omitempty"`           // Producer : dernier message publié
+	Paused               bool       `json:"paused,omitempty"`                 // Consommation suspendue
+	OutsideBusinessHours bool       `json:"outside_business_hours,omitempty"` // Suspendu hors des heures d'ouverture
+	CircuitState         string     `json:"circuit_state,omitempty"`          // Consumer : état du disjoncteur (closed, open, half-open)
+	ErrorStreak          int        `json:"error_streak"`                     // Nombre d'erreurs consécutives
+	LastError            string     `json:"last_error,omitempty"`
+}
+
+// Checker est implémenté par consumer.Consumer et producer.Producer
+type Checker interface {
+	Health() Report
+}
+
+// Response est le corps JSON retourné par /healthz et /readyz
+type Response struct {
+	Status     string   `json:"status"` // "ok" ou "unavailable"
+	Components []Report `json:"components"`
+}
+
+// Handler sert /healthz (200 si tous les composants sont vivants) et
+// /readyz (200 si tous sont prêts) ; 503 sinon
+func Handler(checkers ...Checker) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		respond(w, checkers, func(r Report) bool { return r.Live })
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		respond(w, checkers, func(r Report) bool { return r.Ready })
+	})
+	return mux
+}
+
+func respond(w http.ResponseWriter, checkers []Checker, ok func(Report) bool) {
+	resp := Response{Status: "ok", Components: make([]Report, 0, len(checkers))}
+	for _, c := range checkers {
+		report := c.Health()
+		if !ok(report) {
+			resp.Status = "unavailable"
+		}
+		resp.Components = append(resp.Components, report)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,46 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticChecker Report
+
+func (c staticChecker) Health() Report { return Report(c) }
+
+func TestHandler(t *testing.T) {
+	h := Handler(
+		staticChecker{Name: "consumer:orders", Live: true, Ready: true, BrokerConnected: true},
+		staticChecker{Name: "producer:orders", Live: true, Ready: false, ErrorStreak: 3},
+	)
+
+	tests := []struct {
+		path       string
+		wantCode   int
+		wantStatus string
+	}{
+		{"/healthz", http.StatusOK, "ok"},
+		{"/readyz", http.StatusServiceUnavailable, "unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var resp Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.wantStatus, resp.Status)
+			assert.Len(t, resp.Components, 2)
+		})
+	}
+}
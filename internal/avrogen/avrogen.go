@@ -0,0 +1,8 @@
+// Package avrogen génère, à partir des fichiers .avsc chargés par internal/avsc,
+// les constantes de schéma (package schemas) et les modèles Go (package models).
+// Il est appelé par `go generate` via avro_schemas/cmd/avrogen.
+//
+// Les événements (fichiers à la racine) deviennent des modèles avec GetSchema et
+// PartitionKey ; les types partagés (types/) des types Go simples. L'attribut
+// facultatif "partitionKey" désigne le champ de la clé de partition (défaut : "id").
+package avrogen
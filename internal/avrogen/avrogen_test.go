@@ -0,0 +1,70 @@
+package avrogen
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/internal/avsc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test ./internal/avrogen -update régénère les fichiers de référence
+var update = flag.Bool("update", false, "régénère les fichiers testdata/golden")
+
+const schemasImport = "github.com/METAVENTUS/metaventus-kafka-adapters/avro_schemas/schemas"
+
+func TestGenerate_Golden(t *testing.T) {
+	files, err := avsc.Load(os.DirFS("testdata/schemas"))
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+
+	out, err := Generate(schemasImport, files)
+	require.NoError(t, err)
+
+	for name, got := range map[string][]byte{
+		"schemas_gen.go.golden": out.Schemas,
+		"models_gen.go.golden":  out.Models,
+	} {
+		path := filepath.Join("testdata", "golden", name)
+		if *update {
+			require.NoError(t, os.WriteFile(path, got, 0o644))
+		}
+		want, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got), name)
+	}
+}
+
+// Les fichiers générés du dépôt doivent correspondre aux .avsc (go generate ./avro_schemas)
+func TestGenerate_UpToDate(t *testing.T) {
+	files, err := avsc.Load(os.DirFS("../../avro_schemas/schemas"))
+	require.NoError(t, err)
+	out, err := Generate(schemasImport, files)
+	require.NoError(t, err)
+
+	for path, got := range map[string][]byte{
+		"../../avro_schemas/schemas/schemas_gen.go": out.Schemas,
+		"../../models/models_gen.go":                out.Models,
+	} {
+		want, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got), "%s : relancer go generate ./avro_schemas", path)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	for name, source := range map[string]string{
+		"clé absente":       `{"type": "record", "name": "A", "partitionKey": "x", "fields": [{"name": "id", "type": "string"}]}`,
+		"clé non gérée":     `{"type": "record", "name": "A", "partitionKey": "f", "fields": [{"name": "f", "type": "double"}]}`,
+		"type Go en double": `{"type": "record", "name": "A", "fields": [{"name": "b", "type": {"type": "record", "name": "B", "goType": "A", "fields": []}}]}`,
+	} {
+		files, err := avsc.Load(fstest.MapFS{"a.avsc": {Data: []byte(source)}})
+		require.NoError(t, err, name)
+		_, err = Generate(schemasImport, files)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,335 @@
+package avrogen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/internal/avsc"
+	"github.com/hamba/avro"
+)
+
+// header précède chaque fichier généré (reconnu par les outils Go)
+const header = "// Code generated by avrogen. DO NOT EDIT.\n\n"
+
+// Output contient les fichiers générés
+type Output struct {
+	Schemas []byte // package schemas : <Type>Name et <Type>Schema
+	Models  []byte // package models : structs, enums, GetSchema et PartitionKey
+}
+
+// Generate produit les fichiers générés pour `files` ; `schemasImport` est le
+// chemin d'import du package schemas
+func Generate(schemasImport string, files []avsc.File) (Output, error) {
+	g := &generator{decls: map[string]*decl{}, goNames: map[string]string{}, imports: map[string]bool{}, files: map[string]string{}}
+	for _, f := range files {
+		for _, name := range f.Defines {
+			g.files[name] = f.Path
+		}
+	}
+	for _, f := range files {
+		if f.Shared {
+			if _, err := g.goType(f.Path, f.Schema); err != nil {
+				return Output{}, err
+			}
+			continue
+		}
+		if err := g.event(f); err != nil {
+			return Output{}, err
+		}
+	}
+
+	var out Output
+	var err error
+	if out.Schemas, err = g.schemas(); err != nil {
+		return Output{}, err
+	}
+	if out.Models, err = g.models(schemasImport); err != nil {
+		return Output{}, err
+	}
+	return out, nil
+}
+
+// decl est un type Go à générer (record, enum ou fixed)
+type decl struct {
+	goName string
+	schema avro.NamedSchema
+	file   string
+	fields []field // record
+	event  *event  // record de premier niveau
+}
+
+type field struct {
+	goName, avroName, goType, doc string
+}
+
+// event décrit un record de premier niveau, qui implémente models.AvroEvent
+type event struct {
+	partitionKey *field
+}
+
+type generator struct {
+	order   []*decl
+	decls   map[string]*decl  // par nom complet Avro
+	goNames map[string]string // nom Go → nom complet Avro
+	imports map[string]bool
+	events  []*decl
+	files   map[string]string // fichier de définition, par nom complet Avro
+}
+
+// event déclare le record de premier niveau de `f` et ses types imbriqués
+func (g *generator) event(f avsc.File) error {
+	if _, err := g.goType(f.Path, f.Schema); err != nil {
+		return err
+	}
+	d := g.decls[f.Schema.FullName()]
+	d.event = &event{}
+
+	key, _ := f.Schema.(*avro.RecordSchema).Prop(avsc.PropPartitionKey).(string)
+	explicit := key != ""
+	if !explicit {
+		key = "id"
+	}
+	for i := range d.fields {
+		if d.fields[i].avroName == key {
+			d.event.partitionKey = &d.fields[i]
+		}
+	}
+	switch {
+	case d.event.partitionKey == nil && explicit:
+		return fmt.Errorf("%s : partitionKey %q : champ introuvable", f.Path, key)
+	case d.event.partitionKey != nil && partitionKeyExpr("e", *d.event.partitionKey) == "":
+		if explicit {
+			return fmt.Errorf("%s : partitionKey %q : type %s non supporté", f.Path, key, d.event.partitionKey.goType)
+		}
+		d.event.partitionKey = nil
+	}
+	if d.event.partitionKey != nil && strings.HasPrefix(d.event.partitionKey.goType, "int") {
+		g.imports["strconv"] = true
+	}
+	g.events = append(g.events, d)
+	return nil
+}
+
+// goType retourne le type Go de `schema`, en déclarant au besoin les types nommés
+func (g *generator) goType(file string, schema avro.Schema) (string, error) {
+	switch s := schema.(type) {
+	case *avro.RefSchema:
+		return g.goType(file, s.Schema())
+	case *avro.PrimitiveSchema:
+		if t := g.logicalType(s.Logical()); t != "" {
+			return t, nil
+		}
+		switch s.Type() {
+		case avro.Boolean:
+			return "bool", nil
+		case avro.Int:
+			return "int", nil
+		case avro.Long:
+			return "int64", nil
+		case avro.Float:
+			return "float32", nil
+		case avro.Double:
+			return "float64", nil
+		case avro.Bytes:
+			return "[]byte", nil
+		case avro.String:
+			return "string", nil
+		}
+		return "", fmt.Errorf("%s : type %s non supporté hors union", file, s.Type())
+	case *avro.ArraySchema:
+		t, err := g.goType(file, s.Items())
+		return "[]" + t, err
+	case *avro.MapSchema:
+		t, err := g.goType(file, s.Values())
+		return "map[string]" + t, err
+	case *avro.UnionSchema:
+		if !s.Nullable() {
+			return "any", nil
+		}
+		_, i := s.Indices()
+		t, err := g.goType(file, s.Types()[i])
+		if err != nil || strings.HasPrefix(t, "*") {
+			return t, err
+		}
+		return "*" + t, nil
+	case *avro.FixedSchema:
+		if t := g.logicalType(s.Logical()); t != "" {
+			return t, nil
+		}
+		return g.declare(file, s)
+	case *avro.EnumSchema:
+		return g.declare(file, s)
+	case *avro.RecordSchema:
+		return g.declare(file, s)
+	}
+	return "", fmt.Errorf("%s : type %s non supporté", file, schema.Type())
+}
+
+// logicalType retourne le type Go d'un type logique ("" si le type de base convient)
+func (g *generator) logicalType(logical avro.LogicalSchema) string {
+	if logical == nil {
+		return ""
+	}
+	switch logical.Type() {
+	case avro.Date, avro.TimestampMillis, avro.TimestampMicros:
+		g.imports["time"] = true
+		return "time.Time"
+	case avro.TimeMillis, avro.TimeMicros:
+		g.imports["time"] = true
+		return "time.Duration"
+	case avro.UUID:
+		g.imports["github.com/google/uuid"] = true
+		return "uuid.UUID"
+	case avro.Decimal:
+		g.imports["math/big"] = true
+		return "*big.Rat"
+	}
+	return ""
+}
+
+// declare enregistre un type nommé (une seule fois) et retourne son nom Go
+func (g *generator) declare(file string, schema avro.NamedSchema) (string, error) {
+	if d, ok := g.decls[schema.FullName()]; ok {
+		if d.schema.Fingerprint() != schema.Fingerprint() {
+			return "", fmt.Errorf("%s : %s diffère de sa définition dans %s", file, schema.FullName(), d.file)
+		}
+		return d.goName, nil
+	}
+
+	name := avsc.GoName(schema)
+	if other, ok := g.goNames[name]; ok {
+		return "", fmt.Errorf("%s : type Go %s déjà utilisé par %s (attribut goType)", file, name, other)
+	}
+	if defined, ok := g.files[schema.FullName()]; ok {
+		file = defined
+	}
+	d := &decl{goName: name, schema: schema, file: file}
+	g.decls[schema.FullName()] = d
+	g.goNames[name] = schema.FullName()
+	g.order = append(g.order, d)
+
+	if record, ok := schema.(*avro.RecordSchema); ok {
+		for _, f := range record.Fields() {
+			t, err := g.goType(file, f.Type())
+			if err != nil {
+				return "", fmt.Errorf("%s.%s : %w", schema.FullName(), f.Name(), err)
+			}
+			d.fields = append(d.fields, field{goName: avsc.Identifier(f.Name()), avroName: f.Name(), goType: t, doc: f.Doc()})
+		}
+	}
+	return name, nil
+}
+
+// schemas génère le nom et l'accès à la définition de chaque événement
+func (g *generator) schemas() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(header + "package schemas\n")
+	for _, d := range g.events {
+		fmt.Fprintf(&b, "\n// %sName est le nom du schéma %s (%s)\nconst %sName = %q\n", d.goName, d.schema.FullName(), d.file, d.goName, d.goName)
+		fmt.Fprintf(&b, "\n// %sSchema est la définition autonome du schéma %s\nvar %sSchema = mustJSON(%sName)\n", d.goName, d.schema.FullName(), d.goName, d.goName)
+	}
+	return source(&b)
+}
+
+// models génère les types Go et les méthodes des événements
+func (g *generator) models(schemasImport string) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(header + "package models\n\n")
+	// bibliothèque standard, puis modules externes
+	var std, ext []string
+	for imp := range g.imports {
+		if strings.Contains(imp, ".") {
+			ext = append(ext, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	ext = append(ext, schemasImport)
+	sort.Strings(std)
+	sort.Strings(ext)
+	b.WriteString("import (\n")
+	for _, imp := range std {
+		fmt.Fprintf(&b, "%q\n", imp)
+	}
+	if len(std) > 0 {
+		b.WriteString("\n")
+	}
+	for _, imp := range ext {
+		fmt.Fprintf(&b, "%q\n", imp)
+	}
+	b.WriteString(")\n")
+
+	for _, d := range g.order {
+		switch s := d.schema.(type) {
+		case *avro.RecordSchema:
+			writeRecord(&b, d, s)
+		case *avro.EnumSchema:
+			fmt.Fprintf(&b, "\n// %s est généré depuis l'enum Avro %s (%s)\n", d.goName, s.FullName(), d.file)
+			fmt.Fprintf(&b, "type %s string\n\n// Valeurs de %s\nconst (\n", d.goName, d.goName)
+			for _, symbol := range s.Symbols() {
+				fmt.Fprintf(&b, "%s%s %s = %q\n", d.goName, avsc.Identifier(symbol), d.goName, symbol)
+			}
+			b.WriteString(")\n")
+		case *avro.FixedSchema:
+			fmt.Fprintf(&b, "\n// %s est généré depuis le type fixed Avro %s (%s)\n", d.goName, s.FullName(), d.file)
+			fmt.Fprintf(&b, "type %s [%d]byte\n", d.goName, s.Size())
+		}
+	}
+	return source(&b)
+}
+
+func writeRecord(b *bytes.Buffer, d *decl, s *avro.RecordSchema) {
+	fmt.Fprintf(b, "\n// %s est généré depuis le record Avro %s (%s)", d.goName, s.FullName(), d.file)
+	if doc := s.Doc(); doc != "" {
+		fmt.Fprintf(b, "\n//\n// %s", strings.ReplaceAll(doc, "\n", "\n// "))
+	}
+	fmt.Fprintf(b, "\ntype %s struct {\n", d.goName)
+	for _, f := range d.fields {
+		fmt.Fprintf(b, "%s %s `avro:%q`", f.goName, f.goType, f.avroName)
+		if f.doc != "" {
+			fmt.Fprintf(b, " // %s", strings.ReplaceAll(f.doc, "\n", " "))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+
+	if d.event == nil {
+		return
+	}
+	fmt.Fprintf(b, "\n// GetSchema retourne le schéma Avro associé\nfunc (%s) GetSchema() string {\nreturn schemas.%sSchema\n}\n", d.goName, d.goName)
+	if key := d.event.partitionKey; key != nil {
+		fmt.Fprintf(b, "\n// PartitionKey retourne la clé de partition (champ %s)\nfunc (e %s) PartitionKey() string {\nreturn %s\n}\n",
+			key.avroName, d.goName, partitionKeyExpr("e", *key))
+	} else {
+		fmt.Fprintf(b, "\n// PartitionKey retourne une clé vide : la partition est choisie par le Balancer\nfunc (%s) PartitionKey() string {\nreturn \"\"\n}\n", d.goName)
+	}
+}
+
+// partitionKeyExpr retourne l'expression de la clé de partition ("" si le type
+// du champ ne s'y prête pas)
+func partitionKeyExpr(recv string, f field) string {
+	v := recv + "." + f.goName
+	switch f.goType {
+	case "string":
+		return v
+	case "uuid.UUID":
+		return v + ".String()"
+	case "int":
+		return "strconv.Itoa(" + v + ")"
+	case "int64":
+		return "strconv.FormatInt(" + v + ", 10)"
+	}
+	return ""
+}
+
+// source formate le code généré
+func source(b *bytes.Buffer) ([]byte, error) {
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("code généré invalide : %w", err)
+	}
+	return out, nil
+}
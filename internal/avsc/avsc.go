@@ -0,0 +1,319 @@
+// Package avsc charge des fichiers .avsc : événements à la racine, types nommés
+// partagés (ex : Address) dans le répertoire types/. Un schéma peut utiliser un
+// type nommé défini dans un autre fichier ; sa définition y est alors recopiée,
+// pour que chaque schéma soit autonome (Schema Registry, autres langages).
+package avsc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hamba/avro"
+)
+
+// SharedDir est le répertoire des types partagés (ni événements, ni sujets)
+const SharedDir = "types"
+
+// Attributs .avsc propres au dépôt, facultatifs, au niveau d'un type nommé
+const (
+	PropGoType       = "goType"       // nom du type Go (défaut : nom Avro)
+	PropPartitionKey = "partitionKey" // champ retourné par PartitionKey (défaut : "id")
+)
+
+// File est un fichier .avsc chargé
+type File struct {
+	Path    string           // chemin dans le système de fichiers (ex : types/address.avsc)
+	Shared  bool             // type partagé (répertoire types/)
+	Source  string           // définition telle qu'écrite
+	JSON    string           // définition autonome (Source si le fichier ne référence aucun autre fichier)
+	Schema  avro.NamedSchema // schéma analysé depuis JSON ; un record pour un événement
+	GoName  string           // nom du type Go (attribut goType ou nom Avro)
+	Defines []string         // noms complets des types nommés définis par le fichier
+}
+
+// Load charge et valide les fichiers *.avsc et types/*.avsc de `fsys`, triés par chemin
+func Load(fsys fs.FS) ([]File, error) {
+	var paths []string
+	for _, pattern := range []string{"*.avsc", SharedDir + "/*.avsc"} {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	l := &loader{defs: map[string]definition{}}
+	files := make([]File, 0, len(paths))
+	trees := make([]any, 0, len(paths))
+	for _, p := range paths {
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, err
+		}
+		tree, err := decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s : JSON invalide : %w", p, err)
+		}
+		file := File{Path: p, Shared: path.Dir(p) == SharedDir, Source: strings.TrimSpace(string(data))}
+		if err := l.collect(&file, tree, ""); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+		trees = append(trees, tree)
+	}
+
+	for i := range files {
+		if err := l.resolve(&files[i], trees[i]); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// definition est un type nommé et le fichier qui le définit
+type definition struct {
+	node      object
+	namespace string
+	path      string
+}
+
+type loader struct {
+	defs map[string]definition // par nom complet
+}
+
+// collect relève les types nommés définis dans `node`
+func (l *loader) collect(file *File, node any, namespace string) error {
+	switch n := node.(type) {
+	case []any:
+		for _, v := range n {
+			if err := l.collect(file, v, namespace); err != nil {
+				return err
+			}
+		}
+	case object:
+		t, _ := n.get("type")
+		switch t {
+		case "record", "error", "enum", "fixed":
+			full := fullName(n.str("name"), n.str("namespace"), namespace)
+			if def, ok := l.defs[full]; ok && def.path != file.Path {
+				return fmt.Errorf("%s : type %s déjà défini dans %s", file.Path, full, def.path)
+			}
+			l.defs[full] = definition{node: n, namespace: namespaceOf(full), path: file.Path}
+			file.Defines = append(file.Defines, full)
+			fields, _ := n.get("fields")
+			for _, f := range asSlice(fields) {
+				if f, ok := f.(object); ok {
+					ft, _ := f.get("type")
+					if err := l.collect(file, ft, namespaceOf(full)); err != nil {
+						return err
+					}
+				}
+			}
+		case "array":
+			items, _ := n.get("items")
+			return l.collect(file, items, namespace)
+		case "map":
+			values, _ := n.get("values")
+			return l.collect(file, values, namespace)
+		default:
+			return l.collect(file, t, namespace)
+		}
+	}
+	return nil
+}
+
+// resolve rend le schéma de `file` autonome, puis l'analyse et le valide
+func (l *loader) resolve(file *File, tree any) error {
+	e := &expander{loader: l, path: file.Path, defined: map[string]bool{}}
+	expanded := e.expand(tree, "")
+	file.JSON = file.Source
+	if e.inlined {
+		data, err := json.MarshalIndent(expanded, "", "  ")
+		if err != nil {
+			return fmt.Errorf("%s : %w", file.Path, err)
+		}
+		file.JSON = string(data)
+	}
+
+	// Cache vierge : le schéma doit se suffire à lui-même
+	schema, err := avro.ParseWithCache(file.JSON, "", &avro.SchemaCache{})
+	if err != nil {
+		return fmt.Errorf("%s : schéma invalide : %w", file.Path, err)
+	}
+	named, ok := schema.(avro.NamedSchema)
+	if !ok || (!file.Shared && schema.Type() != avro.Record) {
+		return fmt.Errorf("%s : un événement doit être un record, un type partagé un type nommé (%s)", file.Path, schema.Type())
+	}
+	file.Schema = named
+	file.GoName = GoName(named)
+	return nil
+}
+
+// expander recopie dans un schéma les types nommés définis dans d'autres fichiers
+type expander struct {
+	*loader
+	path    string
+	defined map[string]bool // types nommés déjà définis dans le schéma produit
+	inlined bool
+}
+
+func (e *expander) expand(node any, namespace string) any {
+	switch n := node.(type) {
+	case string:
+		full := fullName(n, "", namespace)
+		def, ok := e.defs[full]
+		if isPrimitive(n) || e.defined[full] || !ok || def.path == e.path {
+			return n
+		}
+		// Type d'un autre fichier : sa définition est recopiée à la première utilisation
+		e.inlined = true
+		node := def.node
+		if !strings.Contains(node.str("name"), ".") && node.str("namespace") == "" && def.namespace != "" {
+			node = node.with("namespace", def.namespace)
+		}
+		return e.expand(node, def.namespace)
+	case []any:
+		out := make([]any, len(n))
+		for i, v := range n {
+			out[i] = e.expand(v, namespace)
+		}
+		return out
+	case object:
+		t, _ := n.get("type")
+		switch t {
+		case "record", "error", "enum", "fixed":
+			full := fullName(n.str("name"), n.str("namespace"), namespace)
+			if e.defined[full] {
+				return full
+			}
+			e.defined[full] = true
+			fields, ok := n.get("fields")
+			if !ok {
+				return n
+			}
+			out := make([]any, 0, len(asSlice(fields)))
+			for _, f := range asSlice(fields) {
+				if f, ok := f.(object); ok {
+					ft, _ := f.get("type")
+					out = append(out, f.with("type", e.expand(ft, namespaceOf(full))))
+					continue
+				}
+				out = append(out, f)
+			}
+			return n.with("fields", out)
+		case "array":
+			items, _ := n.get("items")
+			return n.with("items", e.expand(items, namespace))
+		case "map":
+			values, _ := n.get("values")
+			return n.with("values", e.expand(values, namespace))
+		case nil:
+			return n
+		default:
+			return n.with("type", e.expand(t, namespace))
+		}
+	}
+	return node
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func isPrimitive(name string) bool {
+	switch avro.Type(name) {
+	case avro.Null, avro.Boolean, avro.Int, avro.Long, avro.Float, avro.Double, avro.Bytes, avro.String:
+		return true
+	}
+	return false
+}
+
+// fullName applique les règles de nommage Avro : un nom contenant un point est
+// complet, sinon il est qualifié par son namespace ou celui du type englobant
+func fullName(name, namespace, enclosing string) string {
+	if strings.Contains(name, ".") {
+		return name
+	}
+	if namespace == "" {
+		namespace = enclosing
+	}
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func namespaceOf(full string) string {
+	if i := strings.LastIndex(full, "."); i >= 0 {
+		return full[:i]
+	}
+	return ""
+}
+
+// GoName retourne le nom Go d'un type nommé (attribut goType ou nom Avro)
+func GoName(schema avro.NamedSchema) string {
+	if p, ok := schema.(interface{ Prop(string) interface{} }); ok {
+		if name, ok := p.Prop(PropGoType).(string); ok && name != "" {
+			return name
+		}
+	}
+	return Identifier(schema.Name())
+}
+
+// initialisms sont écrits en majuscules dans les identifiants Go
+var initialisms = map[string]bool{
+	"ID": true, "UUID": true, "URL": true, "URI": true, "API": true, "HTTP": true,
+	"JSON": true, "SQL": true, "IP": true, "SKU": true, "VAT": true,
+}
+
+// Identifier convertit un nom Avro (snake_case, camelCase ou MAJUSCULES) en
+// identifiant Go exporté : user_id → UserID, IN_PROGRESS → InProgress
+func Identifier(name string) string {
+	if strings.ToUpper(name) == name {
+		name = strings.ToLower(name)
+	}
+	var b strings.Builder
+	for _, word := range words(name) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		r := []rune(word)
+		b.WriteRune(unicode.ToUpper(r[0]))
+		b.WriteString(string(r[1:]))
+	}
+	return b.String()
+}
+
+// words découpe `name` aux séparateurs (_ - .) et aux passages minuscule → majuscule
+func words(name string) []string {
+	var (
+		out  []string
+		word []rune
+	)
+	flush := func() {
+		if len(word) > 0 {
+			out = append(out, string(word))
+			word = nil
+		}
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '-' || r == '.':
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && len(word) > 0 && unicode.IsLower(word[len(word)-1]):
+			flush()
+		}
+		word = append(word, r)
+	}
+	flush()
+	return out
+}
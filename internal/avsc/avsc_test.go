@@ -0,0 +1,98 @@
+package avsc
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/hamba/avro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const addressSchema = `{
+  "type": "record",
+  "name": "Address",
+  "namespace": "com.metaventus.common",
+  "fields": [
+    {"name": "city", "type": "string"},
+    {"name": "country", "type": {"type": "enum", "name": "Country", "symbols": ["FR", "BE"]}}
+  ]
+}`
+
+const orderSchema = `{
+  "type": "record",
+  "name": "OrderPlaced",
+  "namespace": "com.metaventus.orders",
+  "fields": [
+    {"name": "id", "type": "string"},
+    {"name": "shipping", "type": "com.metaventus.common.Address"},
+    {"name": "billing", "type": ["null", "com.metaventus.common.Address"], "default": null},
+    {"name": "origin", "type": "com.metaventus.common.Country"}
+  ]
+}`
+
+func TestLoad_SharedTypes(t *testing.T) {
+	files, err := Load(fstest.MapFS{
+		"order_placed.avsc":  {Data: []byte(orderSchema)},
+		"types/address.avsc": {Data: []byte(addressSchema)},
+		"README.md":          {Data: []byte("ignoré")},
+	})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	order, address := files[0], files[1]
+	assert.Equal(t, "order_placed.avsc", order.Path)
+	assert.False(t, order.Shared)
+	assert.Equal(t, "OrderPlaced", order.GoName)
+	assert.Equal(t, []string{"com.metaventus.orders.OrderPlaced"}, order.Defines)
+
+	assert.True(t, address.Shared)
+	assert.Equal(t, []string{"com.metaventus.common.Address", "com.metaventus.common.Country"}, address.Defines)
+	assert.Equal(t, address.Source, address.JSON, "aucune référence externe : définition inchangée")
+
+	// le schéma est autonome : Address (et Country) recopiés une seule fois
+	assert.NotEqual(t, order.Source, order.JSON)
+	standalone, err := avro.Parse(order.JSON)
+	require.NoError(t, err)
+	assert.Equal(t, order.Schema.Fingerprint(), standalone.Fingerprint())
+
+	fields := order.Schema.(*avro.RecordSchema).Fields()
+	shipping := fields[1].Type().(*avro.RecordSchema)
+	assert.Equal(t, "com.metaventus.common.Address", shipping.FullName())
+	assert.Equal(t, avro.Enum, fields[3].Type().Type())
+}
+
+func TestLoad_Errors(t *testing.T) {
+	for name, fsys := range map[string]fstest.MapFS{
+		"type inconnu": {
+			"a.avsc": {Data: []byte(`{"type": "record", "name": "A", "fields": [{"name": "b", "type": "B"}]}`)},
+		},
+		"type défini deux fois": {
+			"a.avsc":       {Data: []byte(`{"type": "record", "name": "A", "fields": []}`)},
+			"types/a.avsc": {Data: []byte(`{"type": "enum", "name": "A", "symbols": ["X"]}`)},
+		},
+		"événement non record": {
+			"a.avsc": {Data: []byte(`{"type": "enum", "name": "A", "symbols": ["X"]}`)},
+		},
+		"JSON invalide": {
+			"a.avsc": {Data: []byte(`{"type": "record"`)},
+		},
+	} {
+		_, err := Load(fsys)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	for in, want := range map[string]string{
+		"id":          "ID",
+		"user_id":     "UserID",
+		"userId":      "UserID",
+		"createdAt":   "CreatedAt",
+		"IN_PROGRESS": "InProgress",
+		"url":         "URL",
+		"UserCreated": "UserCreated",
+	} {
+		assert.Equal(t, want, Identifier(in), in)
+	}
+}
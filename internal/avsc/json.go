@@ -0,0 +1,121 @@
+package avsc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// object est un objet JSON dont l'ordre des clés est conservé, pour que les
+// schémas rendus autonomes restent lisibles (name, type, fields…)
+type object []member
+
+type member struct {
+	key   string
+	value any
+}
+
+func (o object) get(key string) (any, bool) {
+	for _, m := range o {
+		if m.key == key {
+			return m.value, true
+		}
+	}
+	return nil, false
+}
+
+func (o object) str(key string) string {
+	v, _ := o.get(key)
+	s, _ := v.(string)
+	return s
+}
+
+// with retourne une copie de `o` où `key` vaut `value` (ajoutée après "name" si absente)
+func (o object) with(key string, value any) object {
+	out := make(object, 0, len(o)+1)
+	_, found := o.get(key)
+	for _, m := range o {
+		if m.key == key {
+			m.value = value
+		}
+		out = append(out, m)
+		if m.key == "name" && !found {
+			out = append(out, member{key, value})
+			found = true
+		}
+	}
+	if !found {
+		out = append(out, member{key, value})
+	}
+	return out
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(m.key)
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// decode lit un document JSON (objets en `object`, nombres en json.Number)
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("contenu inattendu après le schéma")
+	}
+	return v, nil
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		o := object{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			o = append(o, member{key.(string), value})
+		}
+		_, err := dec.Token()
+		return o, err
+	case json.Delim('['):
+		a := []any{}
+		for dec.More() {
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, value)
+		}
+		_, err := dec.Token()
+		return a, err
+	}
+	return tok, nil
+}
@@ -0,0 +1,93 @@
+// Package ratelimit fournit un limiteur de débit (token bucket) global ou par
+// clé, partagé par le Consumer et le Producer.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// sweepThreshold est le nombre de clés au-delà duquel les limiteurs inactifs sont purgés
+const sweepThreshold = 1024
+
+// Limiter limite le débit à `perSecond` messages par seconde, globalement ou
+// pour chaque clé. Un Limiter nil ne limite rien.
+type Limiter struct {
+	limit  rate.Limit
+	burst  int
+	global *rate.Limiter // nil si le débit est limité par clé
+
+	mu   sync.Mutex
+	keys map[string]*keyLimiter
+}
+
+type keyLimiter struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// New crée un limiteur ; retourne nil si perSecond <= 0.
+// `burst` est le nombre de messages autorisés d'un coup (par défaut : le débit
+// arrondi au supérieur).
+func New(perSecond float64, burst int, perKey bool) *Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(perSecond))
+	}
+
+	l := &Limiter{limit: rate.Limit(perSecond), burst: burst}
+	if perKey {
+		l.keys = make(map[string]*keyLimiter)
+	} else {
+		l.global = rate.NewLimiter(l.limit, burst)
+	}
+	return l
+}
+
+// Wait bloque jusqu'à ce qu'un message de clé `key` puisse passer
+// (la clé est ignorée pour un limiteur global)
+func (l *Limiter) Wait(ctx context.Context, key string) error {
+	if l == nil {
+		return nil
+	}
+	if l.global != nil {
+		return l.global.Wait(ctx)
+	}
+	return l.forKey(key).Wait(ctx)
+}
+
+// forKey retourne le limiteur de la clé, en le créant si besoin
+func (l *Limiter) forKey(key string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if len(l.keys) >= sweepThreshold {
+		l.sweep(now)
+	}
+
+	k, ok := l.keys[key]
+	if !ok {
+		k = &keyLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.keys[key] = k
+	}
+	k.lastUsed = now
+	return k.limiter
+}
+
+// sweep supprime les limiteurs restés inactifs le temps de remplir leur bucket :
+// un nouveau limiteur leur serait équivalent
+func (l *Limiter) sweep(now time.Time) {
+	refill := time.Duration(float64(l.burst) / float64(l.limit) * float64(time.Second))
+	for key, k := range l.keys {
+		if now.Sub(k.lastUsed) > refill {
+			delete(l.keys, key)
+		}
+	}
+}
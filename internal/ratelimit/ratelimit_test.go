@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Global(t *testing.T) {
+	l := New(50, 1, false)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, l.Wait(ctx, "k"))
+	}
+	// 1 message immédiat puis 4 espacés de 20ms
+	assert.GreaterOrEqual(t, time.Since(start), 70*time.Millisecond)
+}
+
+func TestLimiter_PerKey(t *testing.T) {
+	l := New(1, 1, true)
+	ctx := context.Background()
+
+	// chaque clé dispose de son propre bucket
+	require.NoError(t, l.Wait(ctx, "a"))
+	require.NoError(t, l.Wait(ctx, "b"))
+
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, l.Wait(ctx, "a"))
+}
+
+func TestLimiter_NilDoesNotLimit(t *testing.T) {
+	l := New(0, 0, false)
+	assert.Nil(t, l)
+	assert.NoError(t, l.Wait(context.Background(), "k"))
+}
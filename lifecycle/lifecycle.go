@@ -0,0 +1,35 @@
+// Package lifecycle aide les fonctions main à arrêter proprement les Consumers
+// et Producers à la réception de SIGINT/SIGTERM.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// RunUntilSignal bloque jusqu'à SIGINT/SIGTERM (ou l'annulation de ctx), puis
+// ferme les composants dans l'ordre donné : passer les consumers avant les
+// producers pour que les handlers en cours puissent encore publier.
+// Un second signal pendant l'arrêt interrompt le processus immédiatement.
+func RunUntilSignal(ctx context.Context, components ...io.Closer) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	stop() // rétablit le comportement par défaut pour un second signal
+
+	return CloseAll(components...)
+}
+
+// CloseAll ferme les composants dans l'ordre et agrège leurs erreurs
+func CloseAll(components ...io.Closer) error {
+	var errs []error
+	for _, c := range components {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
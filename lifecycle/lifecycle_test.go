@@ -0,0 +1,52 @@
+//go:build unix
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func TestRunUntilSignal_ClosesInOrder(t *testing.T) {
+	var order []string
+	errProducer := errors.New("flush impossible")
+
+	done := make(chan error)
+	go func() {
+		done <- RunUntilSignal(context.Background(),
+			closerFunc(func() error { order = append(order, "consumer"); return nil }),
+			closerFunc(func() error { order = append(order, "producer"); return errProducer }),
+		)
+	}()
+
+	// laisse le temps à signal.NotifyContext de s'abonner
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, errProducer)
+		assert.Equal(t, []string{"consumer", "producer"}, order)
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunUntilSignal ne s'est pas arrêté après SIGTERM")
+	}
+}
+
+func TestRunUntilSignal_StopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var closed bool
+	err := RunUntilSignal(ctx, closerFunc(func() error { closed = true; return nil }))
+	assert.NoError(t, err)
+	assert.True(t, closed)
+}
@@ -0,0 +1,44 @@
+// Package metrics définit l'interface de collecte des mesures des Consumers et
+// Producers, sans imposer de dépendance à un système de monitoring particulier.
+// Un adaptateur Prometheus est disponible dans metrics/prommetrics.
+package metrics
+
+import (
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Collector reçoit les mesures émises par les Consumers et Producers.
+// Les implémentations peuvent embarquer Nop pour ne traiter qu'une partie des mesures.
+type Collector interface {
+	MessageConsumed(topic string, partition int, bytes int) // Message lu sur Kafka
+	DecodeError(topic string)                               // Échec du décodage Avro
+	HandlerError(topic string)                              // Le handler a retourné une erreur
+	HandlerDuration(topic string, d time.Duration)          // Durée d'exécution du handler
+	MessageProduced(topic string, bytes int)                // Message publié avec succès
+	ProduceError(topic string)                              // Échec de publication
+	Retry(topic string)                                     // Nouvelle tentative de traitement
+	DLQSent(topic string)                                   // Message envoyé en dead letter queue
+	CircuitState(topic string, state string)                // Changement d'état du disjoncteur (closed, open, half-open)
+	ReaderStats(stats kafka.ReaderStats)                    // Statistiques périodiques du kafka.Reader (lag…)
+	WriterStats(stats kafka.WriterStats)                    // Statistiques périodiques du kafka.Writer (batchs, retries…)
+}
+
+// Nop ignore toutes les mesures (collecteur par défaut)
+type Nop struct{}
+
+func (Nop) MessageConsumed(string, int, int)      {}
+func (Nop) DecodeError(string)                    {}
+func (Nop) HandlerError(string)                   {}
+func (Nop) HandlerDuration(string, time.Duration) {}
+func (Nop) MessageProduced(string, int)           {}
+func (Nop) ProduceError(string)                   {}
+func (Nop) Retry(string)                          {}
+func (Nop) DLQSent(string)                        {}
+func (Nop) CircuitState(string, string)           {}
+func (Nop) ReaderStats(kafka.ReaderStats)         {}
+func (Nop) WriterStats(kafka.WriterStats)         {}
+
+// StatsInterval est la période de collecte des statistiques kafka-go
+const StatsInterval = 15 * time.Second
@@ -0,0 +1,147 @@
+// Package prommetrics expose les mesures des Consumers et Producers sous forme
+// de métriques Prometheus.
+package prommetrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/segmentio/kafka-go"
+)
+
+// Collector implémente metrics.Collector avec des métriques Prometheus
+type Collector struct {
+	consumed        *prometheus.CounterVec
+	consumedBytes   *prometheus.CounterVec
+	decodeErrors    *prometheus.CounterVec
+	handlerErrors   *prometheus.CounterVec
+	handlerDuration *prometheus.HistogramVec
+	produced        *prometheus.CounterVec
+	producedBytes   *prometheus.CounterVec
+	produceErrors   *prometheus.CounterVec
+	retries         *prometheus.CounterVec
+	dlq             *prometheus.CounterVec
+	circuitState    *prometheus.GaugeVec
+	lag             *prometheus.GaugeVec
+	batchSize       *prometheus.HistogramVec
+	writerRetries   *prometheus.CounterVec
+	writerErrors    *prometheus.CounterVec
+}
+
+var _ metrics.Collector = (*Collector)(nil)
+
+// New crée les métriques (préfixées par `namespace`) et les enregistre dans `reg`
+func New(reg prometheus.Registerer, namespace string) (*Collector, error) {
+	counter := func(name, help string, labels ...string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "kafka", Name: name, Help: help,
+		}, labels)
+	}
+
+	c := &Collector{
+		consumed:      counter("messages_consumed_total", "Nombre de messages consommés.", "topic", "partition"),
+		consumedBytes: counter("consumed_bytes_total", "Volume des messages consommés, en octets.", "topic"),
+		decodeErrors:  counter("decode_errors_total", "Nombre d'échecs de décodage Avro.", "topic"),
+		handlerErrors: counter("handler_errors_total", "Nombre d'erreurs retournées par les handlers.", "topic"),
+		handlerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "kafka", Name: "handler_duration_seconds",
+			Help:    "Durée d'exécution des handlers.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"topic"}),
+		produced:      counter("messages_produced_total", "Nombre de messages publiés.", "topic"),
+		producedBytes: counter("produced_bytes_total", "Volume des messages publiés, en octets.", "topic"),
+		produceErrors: counter("produce_errors_total", "Nombre d'échecs de publication.", "topic"),
+		retries:       counter("retries_total", "Nombre de nouvelles tentatives de traitement.", "topic"),
+		dlq:           counter("dlq_messages_total", "Nombre de messages envoyés en dead letter queue.", "topic"),
+		circuitState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "kafka", Name: "circuit_breaker_state",
+			Help: "État du disjoncteur du consumer (1 pour l'état courant, 0 sinon).",
+		}, []string{"topic", "state"}),
+		lag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "kafka", Name: "consumer_lag",
+			Help: "Retard du consumer (nombre de messages) par partition.",
+		}, []string{"topic", "partition"}),
+		batchSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "kafka", Name: "writer_batch_size",
+			Help:    "Taille moyenne des batchs envoyés par le writer, par période de collecte.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		}, []string{"topic"}),
+		writerRetries: counter("writer_retries_total", "Nombre de tentatives supplémentaires du writer.", "topic"),
+		writerErrors:  counter("writer_errors_total", "Nombre d'erreurs du writer.", "topic"),
+	}
+
+	for _, collector := range []prometheus.Collector{
+		c.consumed, c.consumedBytes, c.decodeErrors, c.handlerErrors, c.handlerDuration,
+		c.produced, c.producedBytes, c.produceErrors, c.retries, c.dlq, c.circuitState, c.lag,
+		c.batchSize, c.writerRetries, c.writerErrors,
+	} {
+		if err := reg.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *Collector) MessageConsumed(topic string, partition int, bytes int) {
+	c.consumed.WithLabelValues(topic, strconv.Itoa(partition)).Inc()
+	c.consumedBytes.WithLabelValues(topic).Add(float64(bytes))
+}
+
+func (c *Collector) DecodeError(topic string) {
+	c.decodeErrors.WithLabelValues(topic).Inc()
+}
+
+func (c *Collector) HandlerError(topic string) {
+	c.handlerErrors.WithLabelValues(topic).Inc()
+}
+
+func (c *Collector) HandlerDuration(topic string, d time.Duration) {
+	c.handlerDuration.WithLabelValues(topic).Observe(d.Seconds())
+}
+
+func (c *Collector) MessageProduced(topic string, bytes int) {
+	c.produced.WithLabelValues(topic).Inc()
+	c.producedBytes.WithLabelValues(topic).Add(float64(bytes))
+}
+
+func (c *Collector) ProduceError(topic string) {
+	c.produceErrors.WithLabelValues(topic).Inc()
+}
+
+func (c *Collector) Retry(topic string) {
+	c.retries.WithLabelValues(topic).Inc()
+}
+
+func (c *Collector) DLQSent(topic string) {
+	c.dlq.WithLabelValues(topic).Inc()
+}
+
+// CircuitState passe à 1 la série de l'état courant et à 0 celles des autres états
+func (c *Collector) CircuitState(topic string, state string) {
+	for _, s := range []string{"closed", "open", "half-open"} {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+		c.circuitState.WithLabelValues(topic, s).Set(value)
+	}
+}
+
+// ReaderStats met à jour le lag de la dernière partition lue par le reader
+func (c *Collector) ReaderStats(stats kafka.ReaderStats) {
+	if stats.Partition == "" {
+		return
+	}
+	c.lag.WithLabelValues(stats.Topic, stats.Partition).Set(float64(stats.Lag))
+}
+
+// WriterStats enregistre les batchs, retries et erreurs depuis la dernière collecte
+func (c *Collector) WriterStats(stats kafka.WriterStats) {
+	if stats.BatchSize.Count > 0 {
+		c.batchSize.WithLabelValues(stats.Topic).Observe(float64(stats.BatchSize.Avg))
+	}
+	c.writerRetries.WithLabelValues(stats.Topic).Add(float64(stats.Retries))
+	c.writerErrors.WithLabelValues(stats.Topic).Add(float64(stats.Errors))
+}
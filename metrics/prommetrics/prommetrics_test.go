@@ -0,0 +1,33 @@
+package prommetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c, err := New(reg, "test")
+	require.NoError(t, err)
+
+	c.MessageConsumed("orders", 2, 100)
+	c.MessageConsumed("orders", 2, 50)
+	c.HandlerError("orders")
+	c.HandlerDuration("orders", 20*time.Millisecond)
+	c.ReaderStats(kafka.ReaderStats{Topic: "orders", Partition: "2", Lag: 42})
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(c.consumed.WithLabelValues("orders", "2")))
+	assert.Equal(t, 150.0, testutil.ToFloat64(c.consumedBytes.WithLabelValues("orders")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.handlerErrors.WithLabelValues("orders")))
+	assert.Equal(t, 42.0, testutil.ToFloat64(c.lag.WithLabelValues("orders", "2")))
+
+	// un second enregistrement dans le même registre échoue
+	_, err = New(reg, "test")
+	assert.Error(t, err)
+}
@@ -5,3 +5,15 @@ type AvroEvent interface {
 	GetSchema() string    // Retourne le schéma Avro
 	PartitionKey() string // Retourne la clé de partition (optionnelle)
 }
+
+// Validator peut être implémenté par un AvroEvent pour être validé avant publication
+type Validator interface {
+	Validate() error // Retourne une erreur si l'événement est invalide
+}
+
+// KeyedEvent peut être implémenté par un AvroEvent dont la clé du message est
+// encodée en Avro (tables Kafka Connect, ksqlDB) plutôt que PartitionKey()
+type KeyedEvent interface {
+	GetKeySchema() string // Retourne le schéma Avro de la clé
+	Key() any             // Retourne la clé à encoder (son type est celui de la clé décodée)
+}
@@ -0,0 +1,24 @@
+// Code generated by avrogen. DO NOT EDIT.
+
+package models
+
+import (
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_schemas/schemas"
+)
+
+// ModelExample est généré depuis le record Avro UserCreated (user_created.avsc)
+type ModelExample struct {
+	ID    string `avro:"id"`
+	Email string `avro:"email"`
+	Name  string `avro:"name"`
+}
+
+// GetSchema retourne le schéma Avro associé
+func (ModelExample) GetSchema() string {
+	return schemas.ModelExampleSchema
+}
+
+// PartitionKey retourne la clé de partition (champ id)
+func (e ModelExample) PartitionKey() string {
+	return e.ID
+}
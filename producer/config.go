@@ -14,6 +14,15 @@ type Config struct {
 	Password string   // Mot de passe Kafka (si authentification)
 	SASL     bool     // Activer l'authentification SASL
 	TLS      bool     // Activer TLS (si nécessaire)
+
+	RateLimit       float64 // Messages publiés par seconde (0 : pas de limite), Publish attend son tour
+	RateBurst       int     // Messages autorisés d'un coup (par défaut : RateLimit arrondi)
+	RateLimitPerKey bool    // Applique RateLimit à chaque clé de partition plutôt qu'au Producer
+
+	SchemaRegistryURL      string // Schema Registry : active le format Confluent (vide : Avro brut)
+	SchemaRegistryUsername string // Clé d'API Schema Registry
+	SchemaRegistryPassword string // Secret d'API Schema Registry
+	SubjectNameStrategy    string // topic (défaut), record ou topic_record
 }
 
 // LoadConfigFromEnv construit la Config en lisant les variables d'environnement
@@ -26,6 +35,9 @@ func LoadConfigFromEnv() Config {
 
 	sasl := parseBool(os.Getenv("PRODUCER_SASL"))
 	tls := parseBool(os.Getenv("PRODUCER_TLS"))
+	rateLimitPerKey := parseBool(os.Getenv("PRODUCER_RATE_LIMIT_PER_KEY"))
+	rateLimit := parseFloat(os.Getenv("PRODUCER_RATE_LIMIT"), 0)
+	rateBurst := parseInt(os.Getenv("PRODUCER_RATE_BURST"), 0)
 
 	cfg := Config{
 		Brokers:  brokers,
@@ -34,6 +46,15 @@ func LoadConfigFromEnv() Config {
 		Password: os.Getenv("PRODUCER_PASSWORD"),
 		SASL:     sasl,
 		TLS:      tls,
+
+		RateLimit:       rateLimit,
+		RateBurst:       rateBurst,
+		RateLimitPerKey: rateLimitPerKey,
+
+		SchemaRegistryURL:      os.Getenv("PRODUCER_SCHEMA_REGISTRY_URL"),
+		SchemaRegistryUsername: os.Getenv("PRODUCER_SCHEMA_REGISTRY_USERNAME"),
+		SchemaRegistryPassword: os.Getenv("PRODUCER_SCHEMA_REGISTRY_PASSWORD"),
+		SubjectNameStrategy:    os.Getenv("PRODUCER_SUBJECT_NAME_STRATEGY"),
 	}
 	return cfg
 }
@@ -59,3 +80,15 @@ func parseInt(val string, defaultVal int) int {
 	}
 	return i
 }
+
+// parseFloat convertit une chaîne en float64, renvoyant defaultVal en cas d'erreur ou vide
+func parseFloat(val string, defaultVal float64) float64 {
+	if val == "" {
+		return defaultVal
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return defaultVal
+	}
+	return f
+}
@@ -0,0 +1,19 @@
+package producer
+
+import "errors"
+
+// Erreurs sentinelles, à tester avec errors.Is
+var (
+	// ErrNoBrokers : la configuration ne contient aucun broker
+	ErrNoBrokers = errors.New("aucun broker spécifié")
+	// ErrConnection : le broker principal est injoignable au démarrage
+	ErrConnection = errors.New("erreur de connexion/ping")
+	// ErrInvalidEvent est retournée quand Validate() échoue avant publication
+	ErrInvalidEvent = errors.New("événement invalide")
+	// ErrEncode : l'événement ne peut pas être encodé avec son schéma Avro
+	ErrEncode = errors.New("erreur d'encodage Avro")
+	// ErrWrite : l'écriture sur Kafka a échoué
+	ErrWrite = errors.New("erreur d'envoi Kafka")
+	// ErrRateLimited : le contexte a expiré en attendant la limite de débit
+	ErrRateLimited = errors.New("limite de débit atteinte")
+)
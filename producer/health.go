@@ -0,0 +1,69 @@
+package producer
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/health"
+)
+
+// status suit l'activité du Producer pour Health()
+type status struct {
+	mu sync.Mutex
+
+	closed      bool
+	lastSentAt  time.Time
+	errorStreak int
+	writeStreak int // Échecs consécutifs d'écriture sur Kafka (ErrWrite)
+	lastError   string
+}
+
+func (s *status) sent() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastSentAt = time.Now()
+	s.errorStreak = 0
+	s.writeStreak = 0
+}
+
+func (s *status) failed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errorStreak++
+	// Un événement invalide ou non encodable ne dit rien de la connexion au broker
+	if errors.Is(err, ErrWrite) {
+		s.writeStreak++
+	}
+	s.lastError = err.Error()
+}
+
+func (s *status) setClosed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+// Health retourne l'état courant du Producer.
+// La connexion au broker est considérée perdue tant que la dernière écriture sur
+// Kafka a échoué (les erreurs d'encodage ne comptent pas).
+func (p *Producer) Health() health.Report {
+	s := &p.status
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	connected := s.writeStreak == 0
+	report := health.Report{
+		Name:            "producer:" + p.topic,
+		Live:            !s.closed,
+		Ready:           !s.closed && connected,
+		BrokerConnected: connected,
+		ErrorStreak:     s.errorStreak,
+		LastError:       s.lastError,
+	}
+	if !s.lastSentAt.IsZero() {
+		lastSentAt := s.lastSentAt
+		report.LastSentAt = &lastSentAt
+	}
+	return report
+}
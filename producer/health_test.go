@@ -0,0 +1,38 @@
+package producer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// invalidEvent a un schéma illisible : son encodage échoue
+type invalidEvent struct{}
+
+func (invalidEvent) GetSchema() string    { return "{" }
+func (invalidEvent) PartitionKey() string { return "1" }
+
+func TestHealth_OnlyWriteErrorsDisconnect(t *testing.T) {
+	p := &Producer{topic: "orders", logger: slog.New(slog.NewTextHandler(io.Discard, nil)), metrics: metrics.Nop{}}
+
+	// un événement non encodable n'est pas une perte de connexion
+	err := p.Publish(context.Background(), invalidEvent{})
+	assert.ErrorIs(t, err, ErrEncode)
+	report := p.Health()
+	assert.True(t, report.BrokerConnected)
+	assert.True(t, report.Ready)
+	assert.Equal(t, 1, report.ErrorStreak)
+
+	p.status.failed(errors.Join(ErrWrite, errors.New("broker injoignable")))
+	assert.False(t, p.Health().BrokerConnected)
+
+	p.status.sent()
+	report = p.Health()
+	assert.True(t, report.BrokerConnected)
+	assert.Zero(t, report.ErrorStreak)
+}
@@ -0,0 +1,99 @@
+package producer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers standards posés par StandardHeaders
+const (
+	HeaderEventID       = "event_id"
+	HeaderProducedAt    = "produced_at"
+	HeaderSourceService = "source_service"
+)
+
+// Record décrit un message en cours de publication
+type Record struct {
+	Topic     string
+	Event     models.AvroEvent // nil pour PublishTombstone
+	Key       []byte           // PartitionKey() ; un models.KeyedEvent est publié avec sa clé encodée en Avro
+	Headers   []kafka.Header
+	Value     []byte // Renseigné après l'encodage Avro (nil pour une tombstone)
+	Tombstone bool   // Valeur nulle : suppression de la clé dans un topic compacté
+}
+
+// Header retourne la valeur du header `key` (chaîne vide si absent)
+func (r *Record) Header(key string) string {
+	for _, h := range r.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// SetHeader ajoute ou remplace le header `key`
+func (r *Record) SetHeader(key, value string) {
+	for i, h := range r.Headers {
+		if h.Key == key {
+			r.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	r.Headers = append(r.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Interceptor s'exécute autour de chaque publication
+type Interceptor interface {
+	// OnSend est appelé avant l'encodage Avro ; une erreur annule l'envoi.
+	// Le contexte retourné est transmis aux intercepteurs suivants et à OnDelivery.
+	OnSend(ctx context.Context, rec *Record) (context.Context, error)
+	// OnDelivery est appelé après l'envoi (err non nil en cas d'échec)
+	OnDelivery(ctx context.Context, rec *Record, err error)
+}
+
+// BeforeSend crée un Interceptor à partir d'une simple fonction exécutée avant l'envoi
+func BeforeSend(fn func(ctx context.Context, rec *Record) error) Interceptor {
+	return beforeSend(fn)
+}
+
+type beforeSend func(ctx context.Context, rec *Record) error
+
+func (f beforeSend) OnSend(ctx context.Context, rec *Record) (context.Context, error) {
+	return ctx, f(ctx, rec)
+}
+
+func (beforeSend) OnDelivery(context.Context, *Record, error) {}
+
+// StandardHeaders pose les headers event_id (conservé s'il existe déjà),
+// produced_at (RFC 3339, UTC) et source_service sur chaque message
+func StandardHeaders(sourceService string) Interceptor {
+	return BeforeSend(func(_ context.Context, rec *Record) error {
+		if rec.Header(HeaderEventID) == "" {
+			rec.SetHeader(HeaderEventID, uuid.NewString())
+		}
+		rec.SetHeader(HeaderProducedAt, time.Now().UTC().Format(time.RFC3339Nano))
+		rec.SetHeader(HeaderSourceService, sourceService)
+		return nil
+	})
+}
+
+// Validation rejette les événements implémentant models.Validator dont Validate() échoue
+// (les tombstones ne sont pas validées)
+func Validation() Interceptor {
+	return BeforeSend(func(_ context.Context, rec *Record) error {
+		v, ok := rec.Event.(models.Validator)
+		if !ok || rec.Tombstone {
+			return nil
+		}
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("%w : %w", ErrInvalidEvent, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,56 @@
+package producer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatedEvent struct {
+	models.ModelExample
+}
+
+func (e validatedEvent) Validate() error {
+	if e.Email == "" {
+		return errors.New("email obligatoire")
+	}
+	return nil
+}
+
+func TestStandardHeaders(t *testing.T) {
+	rec := &Record{Event: models.ModelExample{ID: "1"}}
+	rec.SetHeader(HeaderEventID, "existing-id")
+
+	_, err := StandardHeaders("billing").OnSend(context.Background(), rec)
+	require.NoError(t, err)
+
+	assert.Equal(t, "existing-id", rec.Header(HeaderEventID))
+	assert.Equal(t, "billing", rec.Header(HeaderSourceService))
+	_, err = time.Parse(time.RFC3339Nano, rec.Header(HeaderProducedAt))
+	assert.NoError(t, err)
+	assert.Len(t, rec.Headers, 3)
+}
+
+func TestValidation(t *testing.T) {
+	interceptor := Validation()
+
+	_, err := interceptor.OnSend(context.Background(), &Record{Event: validatedEvent{}})
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+
+	_, err = interceptor.OnSend(context.Background(), &Record{Event: validatedEvent{models.ModelExample{Email: "john@doe.com"}}})
+	assert.NoError(t, err)
+
+	// les événements sans Validate() ne sont pas concernés
+	_, err = interceptor.OnSend(context.Background(), &Record{Event: models.ModelExample{}})
+	assert.NoError(t, err)
+}
+
+func TestNewProducer_NoBrokers(t *testing.T) {
+	_, err := NewProducer(context.Background(), Config{})
+	assert.ErrorIs(t, err, ErrNoBrokers)
+}
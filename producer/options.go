@@ -0,0 +1,44 @@
+package producer
+
+import (
+	"log/slog"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/metrics"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/registry"
+)
+
+// Option personnalise un Producer à sa création
+type Option func(*Producer)
+
+// WithInterceptors ajoute des intercepteurs exécutés dans l'ordre avant l'envoi,
+// puis dans l'ordre inverse après la livraison
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(p *Producer) {
+		p.interceptors = append(p.interceptors, interceptors...)
+	}
+}
+
+// WithLogger remplace le logger par défaut (slog.Default()).
+// Les envois réussis sont journalisés au niveau Debug uniquement.
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *Producer) {
+		p.logger = logger
+	}
+}
+
+// WithMetrics transmet les mesures du Producer au collecteur fourni
+func WithMetrics(collector metrics.Collector) Option {
+	return func(p *Producer) {
+		p.metrics = collector
+	}
+}
+
+// WithSchemaRegistry encode les messages au format Confluent : le schéma de
+// l'événement est enregistré sous le sujet calculé par `strategy`
+// (TopicNameStrategy si nil), puis son identifiant préfixe chaque message.
+// Prioritaire sur Config.SchemaRegistryURL.
+func WithSchemaRegistry(client *registry.Client, strategy registry.SubjectNameStrategy) Option {
+	return func(p *Producer) {
+		p.serde = registry.NewSerde(client, strategy)
+	}
+}
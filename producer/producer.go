@@ -5,8 +5,14 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/internal/ratelimit"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/metrics"
 	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
-	"log"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/registry"
 
 	"github.com/hamba/avro"
 	"github.com/segmentio/kafka-go"
@@ -15,12 +21,23 @@ import (
 
 // Producer Kafka
 type Producer struct {
-	writer *kafka.Writer
-	topic  string
+	writer       *kafka.Writer
+	topic        string
+	interceptors []Interceptor
+	limiter      *ratelimit.Limiter
+	logger       *slog.Logger
+	metrics      metrics.Collector
+	serde        *registry.Serde
+	status       status
+
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
 }
 
 // NewProducer initialise un Kafka Producer avec authentification
-func NewProducer(ctx context.Context, cfg Config) (*Producer, error) {
+func NewProducer(ctx context.Context, cfg Config, opts ...Option) (*Producer, error) {
 	transport := &kafka.Transport{}
 
 	if cfg.SASL {
@@ -38,14 +55,27 @@ func NewProducer(ctx context.Context, cfg Config) (*Producer, error) {
 	}
 
 	if len(cfg.Brokers) == 0 {
-		return nil, fmt.Errorf("aucun broker spécifié")
+		return nil, ErrNoBrokers
+	}
+
+	var serde *registry.Serde
+	if cfg.SchemaRegistryURL != "" {
+		strategy, err := registry.ParseStrategy(cfg.SubjectNameStrategy)
+		if err != nil {
+			return nil, err
+		}
+		serde = registry.NewSerde(registry.NewClient(registry.Config{
+			URL:      cfg.SchemaRegistryURL,
+			Username: cfg.SchemaRegistryUsername,
+			Password: cfg.SchemaRegistryPassword,
+		}), strategy)
 	}
 
 	primaryBroker := cfg.Brokers[0]
 
 	conn, err := kafka.DialContext(ctx, "tcp", primaryBroker)
 	if err != nil {
-		return nil, fmt.Errorf("erreur de connexion/ping sur %s: %w", primaryBroker, err)
+		return nil, fmt.Errorf("%w sur %s : %w", ErrConnection, primaryBroker, err)
 	}
 	defer conn.Close()
 
@@ -56,36 +86,202 @@ func NewProducer(ctx context.Context, cfg Config) (*Producer, error) {
 		Balancer:  &kafka.LeastBytes{},
 	}
 
-	return &Producer{
-		writer: w,
-		topic:  cfg.Topic,
-	}, nil
+	p := &Producer{
+		writer:  w,
+		topic:   cfg.Topic,
+		limiter: ratelimit.New(cfg.RateLimit, cfg.RateBurst, cfg.RateLimitPerKey),
+		logger:  slog.Default(),
+		metrics: metrics.Nop{},
+		serde:   serde,
+		stop:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.wg.Add(1)
+	go p.collectStats()
+
+	return p, nil
+}
+
+// Close envoie les messages en attente puis ferme le writer (peut être appelé plusieurs fois)
+func (p *Producer) Close() error {
+	p.closeOnce.Do(func() {
+		p.status.setClosed()
+		close(p.stop)
+		p.wg.Wait()
+		p.closeErr = p.writer.Close()
+	})
+	return p.closeErr
 }
 
 // Publish envoie un événement Avro au topic Kafka
+// Les intercepteurs sont exécutés avant l'encodage puis après la livraison.
+// Si un débit maximal est configuré, Publish attend son tour (ou l'annulation de ctx).
 func (p *Producer) Publish(ctx context.Context, event models.AvroEvent) error {
-	schema := event.GetSchema()
+	return p.publish(ctx, &Record{
+		Topic: p.topic,
+		Event: event,
+		Key:   []byte(event.PartitionKey()),
+	})
+}
 
-	// Sérialisation Avro
-	buf := new(bytes.Buffer)
-	encoder, err := avro.NewEncoder(schema, buf)
+// PublishTombstone envoie un message de valeur nulle pour la clé `key`, qui supprime
+// l'entité correspondante d'un topic compacté (clé brute, voir PublishTombstoneFor)
+func (p *Producer) PublishTombstone(ctx context.Context, key string) error {
+	return p.publish(ctx, &Record{
+		Topic:     p.topic,
+		Key:       []byte(key),
+		Tombstone: true,
+	})
+}
+
+// PublishTombstoneFor envoie une tombstone avec la clé de `event` : PartitionKey(),
+// ou sa clé encodée en Avro s'il implémente models.KeyedEvent. Seuls les champs
+// de la clé ont besoin d'être renseignés.
+func (p *Producer) PublishTombstoneFor(ctx context.Context, event models.AvroEvent) error {
+	return p.publish(ctx, &Record{
+		Topic:     p.topic,
+		Event:     event,
+		Key:       []byte(event.PartitionKey()),
+		Tombstone: true,
+	})
+}
+
+// publish applique la limite de débit et les intercepteurs autour de l'envoi de `rec`
+func (p *Producer) publish(ctx context.Context, rec *Record) error {
+	err := p.limiter.Wait(ctx, string(rec.Key))
+	if err != nil {
+		return fmt.Errorf("%w : %w", ErrRateLimited, err)
+	}
+
+	for i, interceptor := range p.interceptors {
+		if ctx, err = interceptor.OnSend(ctx, rec); err != nil {
+			p.delivered(ctx, p.interceptors[:i], rec, err)
+			return err
+		}
+	}
+
+	err = p.send(ctx, rec)
+	p.delivered(ctx, p.interceptors, rec, err)
 	if err != nil {
-		return fmt.Errorf("erreur encodeur Avro : %w", err)
+		p.metrics.ProduceError(p.topic)
+		p.status.failed(err)
+		p.logger.ErrorContext(ctx, "échec de l'envoi du message",
+			slog.String("topic", p.topic),
+			slog.String("key", string(rec.Key)),
+			slog.Any("error", err),
+		)
+		return err
+	}
+
+	p.metrics.MessageProduced(p.topic, len(rec.Value))
+	p.status.sent()
+	p.logger.DebugContext(ctx, "message envoyé",
+		slog.String("topic", p.topic),
+		slog.String("key", string(rec.Key)),
+	)
+	return nil
+}
+
+// send encode l'événement en Avro (au format Confluent avec un Schema Registry)
+// puis l'écrit sur le topic ; une tombstone est écrite sans valeur
+func (p *Producer) send(ctx context.Context, rec *Record) error {
+	if !rec.Tombstone {
+		value, err := p.encode(ctx, rec)
+		if err != nil {
+			return err
+		}
+		rec.Value = value
 	}
 
-	if err = encoder.Encode(event); err != nil {
-		return fmt.Errorf("erreur d'encodage Avro : %w", err)
+	key, err := p.encodeKey(ctx, rec)
+	if err != nil {
+		return err
 	}
 
 	// Envoi du message Kafka avec le topic spécifique
 	err = p.writer.WriteMessages(ctx, kafka.Message{
-		Key:   []byte(event.PartitionKey()),
-		Value: buf.Bytes(),
+		Key:     key,
+		Value:   rec.Value,
+		Headers: rec.Headers,
 	})
 	if err != nil {
-		return fmt.Errorf("erreur d'envoi Kafka : %w", err)
+		return fmt.Errorf("%w : %w", ErrWrite, err)
 	}
-
-	log.Printf("Message envoyé à %s avec clé %s\n", p.topic, event.PartitionKey())
 	return nil
 }
+
+// encode sérialise l'événement : Avro brut, ou format Confluent si un Schema Registry est configuré
+func (p *Producer) encode(ctx context.Context, rec *Record) ([]byte, error) {
+	if p.serde != nil {
+		value, err := p.serde.Serialize(ctx, rec.Topic, rec.Event.GetSchema(), false, rec.Event)
+		if err != nil {
+			return nil, fmt.Errorf("%w : %w", ErrEncode, err)
+		}
+		return value, nil
+	}
+
+	buf := new(bytes.Buffer)
+	encoder, err := avro.NewEncoder(rec.Event.GetSchema(), buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w : schéma invalide : %w", ErrEncode, err)
+	}
+	if err = encoder.Encode(rec.Event); err != nil {
+		return nil, fmt.Errorf("%w : %w", ErrEncode, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeKey retourne la clé du message : PartitionKey(), ou la clé encodée en Avro
+// (au format Confluent avec un Schema Registry) pour un models.KeyedEvent
+func (p *Producer) encodeKey(ctx context.Context, rec *Record) ([]byte, error) {
+	keyed, ok := rec.Event.(models.KeyedEvent)
+	if !ok {
+		return rec.Key, nil
+	}
+
+	if p.serde != nil {
+		key, err := p.serde.Serialize(ctx, rec.Topic, keyed.GetKeySchema(), true, keyed.Key())
+		if err != nil {
+			return nil, fmt.Errorf("%w : clé : %w", ErrEncode, err)
+		}
+		return key, nil
+	}
+
+	schema, err := avro.Parse(keyed.GetKeySchema())
+	if err != nil {
+		return nil, fmt.Errorf("%w : schéma de clé invalide : %w", ErrEncode, err)
+	}
+	key, err := avro.Marshal(schema, keyed.Key())
+	if err != nil {
+		return nil, fmt.Errorf("%w : clé : %w", ErrEncode, err)
+	}
+	return key, nil
+}
+
+// delivered notifie les intercepteurs, dans l'ordre inverse, du résultat de l'envoi
+func (p *Producer) delivered(ctx context.Context, interceptors []Interceptor, rec *Record, err error) {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptors[i].OnDelivery(ctx, rec, err)
+	}
+}
+
+// collectStats transmet périodiquement les statistiques du writer au collecteur
+func (p *Producer) collectStats() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(metrics.StatsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			p.metrics.WriterStats(p.writer.Stats())
+			return
+		case <-ticker.C:
+			p.metrics.WriterStats(p.writer.Stats())
+		}
+	}
+}
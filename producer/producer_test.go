@@ -0,0 +1,54 @@
+package producer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/hamba/avro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const accountKeySchema = `{"type": "record", "name": "AccountKey", "fields": [{"name": "id", "type": "string"}]}`
+
+type accountKey struct {
+	ID string `avro:"id"`
+}
+
+// keyedAccount est un événement dont la clé est encodée en Avro
+type keyedAccount struct {
+	models.ModelExample
+}
+
+func (keyedAccount) GetKeySchema() string { return accountKeySchema }
+func (a keyedAccount) Key() any           { return accountKey{ID: a.ID} }
+
+func TestEncodeKey(t *testing.T) {
+	p := &Producer{}
+
+	rec := &Record{Event: models.ModelExample{ID: "1"}, Key: []byte("1")}
+	key, err := p.encodeKey(context.Background(), rec)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), key)
+
+	rec = &Record{Event: keyedAccount{models.ModelExample{ID: "1"}}, Key: []byte("1")}
+	key, err = p.encodeKey(context.Background(), rec)
+	require.NoError(t, err)
+	var decoded accountKey
+	require.NoError(t, avro.Unmarshal(avro.MustParse(accountKeySchema), key, &decoded))
+	assert.Equal(t, accountKey{ID: "1"}, decoded)
+}
+
+func TestTombstone(t *testing.T) {
+	p := &Producer{}
+
+	// tombstone à clé brute : pas d'événement
+	key, err := p.encodeKey(context.Background(), &Record{Key: []byte("1"), Tombstone: true})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), key)
+
+	// une tombstone n'est pas validée
+	_, err = Validation().OnSend(context.Background(), &Record{Event: validatedEvent{}, Tombstone: true})
+	assert.NoError(t, err)
+}
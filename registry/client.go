@@ -0,0 +1,243 @@
+// Package registry implémente le client du Confluent Schema Registry, les
+// stratégies de nommage des sujets et le format de message Confluent (wire
+// format), partagés par le Producer, le Consumer et avro_kafka_config.
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/hamba/avro"
+)
+
+// Codes d'erreur du Schema Registry
+const (
+	codeSubjectNotFound = 40401
+	codeVersionNotFound = 40402
+	codeSchemaNotFound  = 40403
+)
+
+var (
+	// ErrSubjectNotFound indique qu'aucun schéma n'est enregistré pour un sujet
+	ErrSubjectNotFound = errors.New("sujet introuvable dans le Schema Registry")
+	// ErrSchemaNotFound indique qu'un schéma (identifiant ou définition) est inconnu du Schema Registry
+	ErrSchemaNotFound = errors.New("schéma introuvable dans le Schema Registry")
+)
+
+// Config contient l'adresse et les identifiants du Schema Registry
+type Config struct {
+	URL      string
+	Username string // Clé d'API Schema Registry
+	Password string // Secret d'API Schema Registry
+}
+
+// Client interroge le Schema Registry ; les schémas par identifiant et les
+// identifiants par sujet sont mis en cache (ils sont immuables)
+type Client struct {
+	cfg  Config
+	http *http.Client
+
+	mu      sync.RWMutex
+	schemas map[int]avro.Schema
+	ids     map[subjectSchema]int
+}
+
+type subjectSchema struct {
+	subject, schema string
+}
+
+// NewClient crée un client du Schema Registry
+func NewClient(cfg Config) *Client {
+	return &Client{
+		cfg:     cfg,
+		http:    &http.Client{Timeout: 10 * time.Second},
+		schemas: map[int]avro.Schema{},
+		ids:     map[subjectSchema]int{},
+	}
+}
+
+// schemaRequest est le corps des requêtes d'enregistrement et de vérification
+type schemaRequest struct {
+	Schema string `json:"schema"`
+}
+
+// Register enregistre `schema` sous `subject` et retourne son identifiant ;
+// enregistrer un schéma déjà présent retourne l'identifiant existant
+func (c *Client) Register(ctx context.Context, subject, schema string) (int, error) {
+	key := subjectSchema{subject, schema}
+	c.mu.RLock()
+	id, ok := c.ids[key]
+	c.mu.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	var res struct {
+		ID int `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", schemaRequest{schema}, &res); err != nil {
+		return 0, fmt.Errorf("échec de l'enregistrement du schéma sous %s : %w", subject, err)
+	}
+
+	c.mu.Lock()
+	c.ids[key] = res.ID
+	c.mu.Unlock()
+	return res.ID, nil
+}
+
+// Lookup retourne l'identifiant de `schema` s'il est enregistré sous `subject`
+// (ErrSubjectNotFound ou ErrSchemaNotFound sinon)
+func (c *Client) Lookup(ctx context.Context, subject, schema string) (int, error) {
+	key := subjectSchema{subject, schema}
+	c.mu.RLock()
+	id, ok := c.ids[key]
+	c.mu.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	var res struct {
+		ID int `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject), schemaRequest{schema}, &res); err != nil {
+		return 0, fmt.Errorf("schéma introuvable sous %s : %w", subject, err)
+	}
+
+	c.mu.Lock()
+	c.ids[key] = res.ID
+	c.mu.Unlock()
+	return res.ID, nil
+}
+
+// SchemaByID retourne le schéma d'identifiant `id`
+func (c *Client) SchemaByID(ctx context.Context, id int) (avro.Schema, error) {
+	c.mu.RLock()
+	schema, ok := c.schemas[id]
+	c.mu.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	var res schemaRequest
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/schemas/ids/%d", id), nil, &res); err != nil {
+		return nil, fmt.Errorf("schéma %d : %w", id, err)
+	}
+	schema, err := avro.Parse(res.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("schéma %d invalide : %w", id, err)
+	}
+
+	c.mu.Lock()
+	c.schemas[id] = schema
+	c.mu.Unlock()
+	return schema, nil
+}
+
+// SubjectVersion est un sujet, et la version, sous lequel un schéma est enregistré
+type SubjectVersion struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// Subjects retourne les sujets sous lesquels le schéma `id` est enregistré
+// (ErrSchemaNotFound si l'identifiant est inconnu)
+func (c *Client) Subjects(ctx context.Context, id int) ([]SubjectVersion, error) {
+	var res []SubjectVersion
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/schemas/ids/%d/versions", id), nil, &res); err != nil {
+		return nil, fmt.Errorf("sujets du schéma %d : %w", id, err)
+	}
+	return res, nil
+}
+
+// Latest retourne la dernière version du schéma enregistrée sous `subject`
+func (c *Client) Latest(ctx context.Context, subject string) (string, error) {
+	var res schemaRequest
+	if err := c.do(ctx, http.MethodGet, "/subjects/"+url.PathEscape(subject)+"/versions/latest", nil, &res); err != nil {
+		return "", fmt.Errorf("schéma introuvable pour %s : %w", subject, err)
+	}
+	return res.Schema, nil
+}
+
+// CheckCompatibility teste `schema` contre la dernière version de `subject` selon
+// la règle de compatibilité du sujet ; retourne ErrSubjectNotFound pour un nouveau sujet
+func (c *Client) CheckCompatibility(ctx context.Context, subject, schema string) (bool, []string, error) {
+	var res struct {
+		IsCompatible bool     `json:"is_compatible"`
+		Messages     []string `json:"messages"`
+	}
+	path := "/compatibility/subjects/" + url.PathEscape(subject) + "/versions/latest?verbose=true"
+	if err := c.do(ctx, http.MethodPost, path, schemaRequest{schema}, &res); err != nil {
+		return false, nil, fmt.Errorf("échec de la vérification de compatibilité de %s : %w", subject, err)
+	}
+	return res.IsCompatible, res.Messages, nil
+}
+
+// Error est une erreur retournée par le Schema Registry
+type Error struct {
+	Status  int    // Statut HTTP
+	Code    int    `json:"error_code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Schema Registry : %s (code %d, HTTP %d)", e.Message, e.Code, e.Status)
+}
+
+// Is relie les codes d'erreur du Schema Registry aux erreurs du package
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrSubjectNotFound:
+		return e.Code == codeSubjectNotFound || e.Code == codeVersionNotFound ||
+			(e.Code == 0 && e.Status == http.StatusNotFound)
+	case ErrSchemaNotFound:
+		return e.Code == codeSchemaNotFound
+	}
+	return false
+}
+
+// do envoie une requête authentifiée et décode la réponse JSON dans `out`
+func (c *Client) do(ctx context.Context, method, path string, payload, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("erreur de conversion JSON : %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.cfg.URL+path, body)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la création de la requête : %w", err)
+	}
+	req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'envoi de la requête : %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la lecture de la réponse : %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		regErr := &Error{Status: resp.StatusCode, Message: string(data)}
+		_ = json.Unmarshal(data, regErr)
+		return regErr
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("réponse du Schema Registry invalide : %w", err)
+	}
+	return nil
+}
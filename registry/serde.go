@@ -0,0 +1,183 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hamba/avro"
+)
+
+// magicByte préfixe les messages au format Confluent : 0, identifiant du schéma
+// sur 4 octets (big endian), puis la donnée Avro
+const magicByte = 0
+
+// headerSize est la taille de l'en-tête du format Confluent
+const headerSize = 5
+
+var (
+	// ErrInvalidMessage regroupe les erreurs propres au message (format, schéma
+	// inconnu, sujet inattendu, donnée Avro illisible) : le relire n'y changera rien
+	ErrInvalidMessage = errors.New("message invalide")
+	// ErrNotWireFormat indique qu'un message n'est pas au format Confluent
+	ErrNotWireFormat = errors.New("message hors format Confluent (octet magique absent)")
+	// ErrSubjectMismatch indique que le schéma d'un message n'appartient pas au sujet attendu
+	ErrSubjectMismatch = errors.New("schéma absent du sujet attendu")
+)
+
+// Encode préfixe `payload` de l'en-tête Confluent (octet magique et identifiant du schéma)
+func Encode(id int, payload []byte) []byte {
+	data := make([]byte, headerSize, headerSize+len(payload))
+	data[0] = magicByte
+	binary.BigEndian.PutUint32(data[1:headerSize], uint32(id))
+	return append(data, payload...)
+}
+
+// Decode sépare l'identifiant du schéma de la donnée Avro d'un message au format Confluent
+func Decode(data []byte) (int, []byte, error) {
+	if len(data) < headerSize || data[0] != magicByte {
+		return 0, nil, ErrNotWireFormat
+	}
+	return int(binary.BigEndian.Uint32(data[1:headerSize])), data[headerSize:], nil
+}
+
+// Serde sérialise et désérialise les valeurs Avro au format Confluent, les sujets
+// étant nommés par la stratégie choisie
+type Serde struct {
+	client   *Client
+	strategy SubjectNameStrategy
+
+	mu       sync.RWMutex
+	parsed   map[string]avro.Schema
+	verified map[subjectID]bool
+}
+
+type subjectID struct {
+	subject string
+	id      int
+}
+
+// NewSerde crée un Serde ; `strategy` nil vaut TopicNameStrategy
+func NewSerde(client *Client, strategy SubjectNameStrategy) *Serde {
+	if strategy == nil {
+		strategy = TopicNameStrategy
+	}
+	return &Serde{
+		client:   client,
+		strategy: strategy,
+		parsed:   map[string]avro.Schema{},
+		verified: map[subjectID]bool{},
+	}
+}
+
+// Subject retourne le sujet de `schema` pour `topic` selon la stratégie du Serde
+func (s *Serde) Subject(topic, schema string, key bool) (string, error) {
+	parsed, err := s.parse(schema)
+	if err != nil {
+		return "", err
+	}
+	return subject(s.strategy, topic, parsed, key), nil
+}
+
+// Serialize encode `v` avec `schema`, enregistré au besoin sous le sujet du topic,
+// et retourne le message au format Confluent
+func (s *Serde) Serialize(ctx context.Context, topic, schema string, key bool, v any) ([]byte, error) {
+	parsed, err := s.parse(schema)
+	if err != nil {
+		return nil, err
+	}
+	subj := subject(s.strategy, topic, parsed, key)
+	id, err := s.client.Register(ctx, subj, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := avro.Marshal(parsed, v)
+	if err != nil {
+		return nil, fmt.Errorf("encodage Avro (%s) : %w", subj, err)
+	}
+	return Encode(id, payload), nil
+}
+
+// Deserialize décode dans `v` un message au format Confluent avec le schéma
+// d'écriture désigné par son identifiant, après avoir vérifié que ce schéma
+// appartient au sujet du topic. Les erreurs propres au message enveloppent
+// ErrInvalidMessage ; les autres (Schema Registry indisponible…) sont transitoires.
+func (s *Serde) Deserialize(ctx context.Context, topic string, key bool, data []byte, v any) error {
+	id, payload, err := Decode(data)
+	if err != nil {
+		return fmt.Errorf("%w : %w", ErrInvalidMessage, err)
+	}
+
+	writer, err := s.client.SchemaByID(ctx, id)
+	if errors.Is(err, ErrSchemaNotFound) {
+		return fmt.Errorf("%w : %w", ErrInvalidMessage, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.verify(ctx, subject(s.strategy, topic, writer, key), id); err != nil {
+		return err
+	}
+
+	// Contrairement à avro.Unmarshal, le décodeur refuse une donnée vide
+	if err := avro.NewDecoderForSchema(writer, bytes.NewReader(payload)).Decode(v); err != nil {
+		return fmt.Errorf("%w : décodage Avro (schéma %d) : %w", ErrInvalidMessage, id, err)
+	}
+	return nil
+}
+
+// verify vérifie (une fois par couple sujet, identifiant) que le schéma `id` est
+// enregistré sous `subj`. L'appartenance est lue par identifiant : rechercher la
+// définition sous le sujet imposerait d'en renvoyer le texte exact enregistré.
+func (s *Serde) verify(ctx context.Context, subj string, id int) error {
+	key := subjectID{subj, id}
+	s.mu.RLock()
+	ok := s.verified[key]
+	s.mu.RUnlock()
+	if ok {
+		return nil
+	}
+
+	subjects, err := s.client.Subjects(ctx, id)
+	switch {
+	case errors.Is(err, ErrSchemaNotFound):
+		return fmt.Errorf("%w : %w", ErrInvalidMessage, err)
+	case err != nil:
+		return err
+	}
+	found := false
+	for _, sv := range subjects {
+		found = found || sv.Subject == subj
+	}
+	if !found {
+		return fmt.Errorf("%w : %w : schéma %d, sujet %s", ErrInvalidMessage, ErrSubjectMismatch, id, subj)
+	}
+
+	s.mu.Lock()
+	s.verified[key] = true
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Serde) parse(schema string) (avro.Schema, error) {
+	s.mu.RLock()
+	parsed, ok := s.parsed[schema]
+	s.mu.RUnlock()
+	if ok {
+		return parsed, nil
+	}
+
+	parsed, err := avro.Parse(schema)
+	if err != nil {
+		return nil, fmt.Errorf("schéma invalide : %w", err)
+	}
+	s.mu.Lock()
+	s.parsed[schema] = parsed
+	s.mu.Unlock()
+	return parsed, nil
+}
@@ -0,0 +1,259 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const userSchema = `{
+	"type": "record", "name": "UserCreated", "namespace": "com.metaventus",
+	"fields": [{"name": "id", "type": "string"}, {"name": "email", "type": "string"}]
+}`
+
+type user struct {
+	ID    string `avro:"id"`
+	Email string `avro:"email"`
+}
+
+// fakeRegistry simule les points d'accès du Schema Registry utilisés par Client ;
+// comme le Schema Registry, la recherche sous un sujet compare le texte exact des schémas
+type fakeRegistry struct {
+	mu       sync.Mutex
+	schemas  []string         // schéma d'identifiant i+1
+	subjects map[string][]int // identifiants enregistrés par sujet
+	requests int
+}
+
+func newFakeRegistry(t *testing.T) (*fakeRegistry, *Client) {
+	f := &fakeRegistry{subjects: map[string][]int{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, NewClient(Config{URL: srv.URL})
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+
+	var req schemaRequest
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "schemas/ids/") && strings.HasSuffix(path, "/versions"):
+		id, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, "schemas/ids/"), "/versions"))
+		if id < 1 || id > len(f.schemas) {
+			writeError(w, codeSchemaNotFound)
+			return
+		}
+		versions := []SubjectVersion{}
+		for subject, ids := range f.subjects {
+			for i, v := range ids {
+				if v == id {
+					versions = append(versions, SubjectVersion{Subject: subject, Version: i + 1})
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(versions)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "schemas/ids/"):
+		id, _ := strconv.Atoi(strings.TrimPrefix(path, "schemas/ids/"))
+		if id < 1 || id > len(f.schemas) {
+			writeError(w, codeSchemaNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(schemaRequest{f.schemas[id-1]})
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/versions"):
+		subject := strings.TrimSuffix(strings.TrimPrefix(path, "subjects/"), "/versions")
+		id := f.find(req.Schema, nil)
+		if id == 0 {
+			f.schemas = append(f.schemas, req.Schema)
+			id = len(f.schemas)
+		}
+		if !contains(f.subjects[subject], id) {
+			f.subjects[subject] = append(f.subjects[subject], id)
+		}
+		fmt.Fprintf(w, `{"id":%d}`, id)
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "subjects/"):
+		ids, ok := f.subjects[strings.TrimPrefix(path, "subjects/")]
+		if !ok {
+			writeError(w, codeSubjectNotFound)
+			return
+		}
+		id := f.find(req.Schema, ids)
+		if id == 0 {
+			writeError(w, codeSchemaNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"id":%d}`, id)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// find retourne l'identifiant de `schema` parmi `ids` (tous les schémas si nil), 0 sinon
+func (f *fakeRegistry) find(schema string, ids []int) int {
+	for i, s := range f.schemas {
+		id := i + 1
+		if ids != nil && !contains(ids, id) {
+			continue
+		}
+		if s == schema {
+			return id
+		}
+	}
+	return 0
+}
+
+func contains(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, code int) {
+	w.WriteHeader(http.StatusNotFound)
+	fmt.Fprintf(w, `{"error_code":%d,"message":"introuvable"}`, code)
+}
+
+func TestWireFormat(t *testing.T) {
+	data := Encode(258, []byte{0x0a})
+	assert.Equal(t, []byte{0, 0, 0, 1, 2, 0x0a}, data)
+
+	id, payload, err := Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, 258, id)
+	assert.Equal(t, []byte{0x0a}, payload)
+
+	_, _, err = Decode([]byte{1, 0, 0, 0, 1})
+	assert.ErrorIs(t, err, ErrNotWireFormat)
+	_, _, err = Decode([]byte{0, 0})
+	assert.ErrorIs(t, err, ErrNotWireFormat)
+}
+
+func TestSerde_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakeRegistry(t)
+	serde := NewSerde(client, nil)
+
+	data, err := serde.Serialize(ctx, "orders", userSchema, false, user{ID: "1", Email: "a@b.c"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, f.subjects["orders-value"])
+
+	var got user
+	require.NoError(t, serde.Deserialize(ctx, "orders", false, data, &got))
+	assert.Equal(t, user{ID: "1", Email: "a@b.c"}, got)
+
+	// identifiants, schémas et vérifications sont mis en cache
+	requests := f.requests
+	_, err = serde.Serialize(ctx, "orders", userSchema, false, user{ID: "2"})
+	require.NoError(t, err)
+	require.NoError(t, serde.Deserialize(ctx, "orders", false, data, &got))
+	assert.Equal(t, requests, f.requests)
+}
+
+// Le texte d'un schéma avec valeurs par défaut, doc et types logiques diffère de
+// sa forme canonique : la vérification du sujet ne doit pas en dépendre
+func TestSerde_RoundTrip_RichSchema(t *testing.T) {
+	const schema = `{
+		"type": "record", "name": "OrderPlaced", "namespace": "com.metaventus", "doc": "Commande",
+		"fields": [
+			{"name": "id", "type": {"type": "string", "logicalType": "uuid"}},
+			{"name": "placed_at", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+			{"name": "note", "type": ["null", "string"], "default": null, "doc": "Commentaire"}
+		]
+	}`
+	type order struct {
+		ID       string    `avro:"id"`
+		PlacedAt time.Time `avro:"placed_at"`
+		Note     *string   `avro:"note"`
+	}
+
+	ctx := context.Background()
+	_, client := newFakeRegistry(t)
+	serde := NewSerde(client, nil)
+	want := order{ID: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", PlacedAt: time.UnixMilli(1700000000000).UTC()}
+	data, err := serde.Serialize(ctx, "orders", schema, false, want)
+	require.NoError(t, err)
+
+	var got order
+	require.NoError(t, NewSerde(client, nil).Deserialize(ctx, "orders", false, data, &got))
+	assert.Equal(t, want, got)
+}
+
+func TestSerde_Strategies(t *testing.T) {
+	ctx := context.Background()
+	for strategy, subject := range map[string]string{
+		"topic":        "orders-key",
+		"record":       "com.metaventus.UserCreated",
+		"topic_record": "orders-com.metaventus.UserCreated",
+	} {
+		f, client := newFakeRegistry(t)
+		s, err := ParseStrategy(strategy)
+		require.NoError(t, err)
+
+		_, err = NewSerde(client, s).Serialize(ctx, "orders", userSchema, true, user{})
+		require.NoError(t, err)
+		assert.Contains(t, f.subjects, subject, strategy)
+	}
+
+	// schéma primitif : nommé par son type
+	subject, err := Subject(RecordNameStrategy, "orders", `"string"`, true)
+	require.NoError(t, err)
+	assert.Equal(t, "string", subject)
+
+	_, err = ParseStrategy("inconnue")
+	assert.Error(t, err)
+}
+
+func TestSerde_Deserialize_Invalid(t *testing.T) {
+	ctx := context.Background()
+	_, client := newFakeRegistry(t)
+	serde := NewSerde(client, nil)
+	data, err := serde.Serialize(ctx, "orders", userSchema, false, user{ID: "1"})
+	require.NoError(t, err)
+
+	var got user
+	// schéma absent du sujet du topic
+	err = serde.Deserialize(ctx, "payments", false, data, &got)
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+	assert.ErrorIs(t, err, ErrSubjectMismatch)
+
+	// identifiant inconnu
+	err = serde.Deserialize(ctx, "orders", false, Encode(42, nil), &got)
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+
+	// Avro brut
+	err = serde.Deserialize(ctx, "orders", false, []byte{2, 'a'}, &got)
+	assert.ErrorIs(t, err, ErrNotWireFormat)
+
+	// donnée vide
+	err = serde.Deserialize(ctx, "orders", false, data[:headerSize], &got)
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+}
+
+func TestSerde_Deserialize_RegistryDown(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	var got user
+	err := NewSerde(NewClient(Config{URL: srv.URL}), nil).Deserialize(context.Background(), "orders", false, Encode(1, nil), &got)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidMessage)
+}
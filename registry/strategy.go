@@ -0,0 +1,68 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hamba/avro"
+)
+
+// SubjectNameStrategy calcule le sujet d'un schéma à partir du topic, du nom
+// complet du record (namespace.nom, ou le type d'un schéma primitif) et de la
+// nature du schéma (clé ou valeur).
+// Toute fonction de cette signature peut servir de stratégie personnalisée.
+type SubjectNameStrategy func(topic, recordName string, key bool) string
+
+// TopicNameStrategy : <topic>-key ou <topic>-value (stratégie par défaut de Confluent)
+func TopicNameStrategy(topic, _ string, key bool) string {
+	if key {
+		return topic + "-key"
+	}
+	return topic + "-value"
+}
+
+// RecordNameStrategy : <namespace.record>, quel que soit le topic
+func RecordNameStrategy(_, recordName string, _ bool) string {
+	return recordName
+}
+
+// TopicRecordNameStrategy : <topic>-<namespace.record>
+func TopicRecordNameStrategy(topic, recordName string, _ bool) string {
+	return topic + "-" + recordName
+}
+
+// ParseStrategy retourne la stratégie nommée `name` : topic, record ou topic_record
+// (ou les noms des classes Confluent, ex : TopicNameStrategy) ; vide : TopicNameStrategy
+func ParseStrategy(name string) (SubjectNameStrategy, error) {
+	switch strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name)) {
+	case "", "topic", "topicname", "topicnamestrategy":
+		return TopicNameStrategy, nil
+	case "record", "recordname", "recordnamestrategy":
+		return RecordNameStrategy, nil
+	case "topicrecord", "topicrecordname", "topicrecordnamestrategy":
+		return TopicRecordNameStrategy, nil
+	default:
+		return nil, fmt.Errorf("stratégie de nommage des sujets inconnue %q (topic, record ou topic_record)", name)
+	}
+}
+
+// Subject retourne le sujet de `schema` selon `strategy` (TopicNameStrategy si nil)
+func Subject(strategy SubjectNameStrategy, topic, schema string, key bool) (string, error) {
+	parsed, err := avro.Parse(schema)
+	if err != nil {
+		return "", fmt.Errorf("schéma invalide : %w", err)
+	}
+	return subject(strategy, topic, parsed, key), nil
+}
+
+func subject(strategy SubjectNameStrategy, topic string, schema avro.Schema, key bool) string {
+	if strategy == nil {
+		strategy = TopicNameStrategy
+	}
+	// Comme Confluent, un schéma primitif (ex : clé "string") est nommé par son type
+	recordName := string(schema.Type())
+	if named, ok := schema.(avro.NamedSchema); ok {
+		recordName = named.FullName()
+	}
+	return strategy(topic, recordName, key)
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/avro_kafka_config"
+	"github.com/stretchr/testify/suite"
+	kafkacontainer "github.com/testcontainers/testcontainers-go/modules/kafka"
+)
+
+// TopicsIntegrationSuite : administration des topics (avro_kafka_config) sur un broker réel
+type TopicsIntegrationSuite struct {
+	suite.Suite
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	kafkaContainer *kafkacontainer.KafkaContainer
+	client         *avro_kafka_config.KafkaClient
+}
+
+// SetupSuite : Démarre Kafka
+func (s *TopicsIntegrationSuite) SetupSuite() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	kafkaContainer, err := kafkacontainer.Run(s.ctx, "confluentinc/confluent-local:7.5.0",
+		kafkacontainer.WithClusterID("test-cluster"),
+	)
+	s.Require().NoError(err, "Échec du démarrage du conteneur Kafka")
+	s.kafkaContainer = kafkaContainer
+
+	mappedPort, err := kafkaContainer.MappedPort(s.ctx, "9093")
+	s.Require().NoError(err)
+	host, err := kafkaContainer.Host(s.ctx)
+	s.Require().NoError(err)
+	hostPort := fmt.Sprintf("%s:%d", host, mappedPort.Int())
+	s.Require().NoError(waitForKafkaReady(hostPort, 10), "Kafka n'est pas prêt")
+
+	// broker local : connexion en clair, sans identifiants
+	s.client = avro_kafka_config.NewKafkaClient(avro_kafka_config.Config{BootstrapServers: hostPort})
+}
+
+// TearDownSuite : Arrête Kafka
+func (s *TopicsIntegrationSuite) TearDownSuite() {
+	if s.kafkaContainer != nil {
+		_ = s.kafkaContainer.Terminate(s.ctx)
+	}
+	s.cancel()
+}
+
+// createTopic crée `name` et attend que ses métadonnées soient disponibles
+func (s *TopicsIntegrationSuite) createTopic(name string, configs map[string]string) {
+	created, err := s.client.CreateTopic(s.ctx, avro_kafka_config.TopicSpec{
+		Name: name, Partitions: 1, ReplicationFactor: 1, Configs: configs,
+	})
+	s.Require().NoError(err)
+	s.Require().True(created)
+	s.Require().Eventually(func() bool {
+		_, err := s.client.DescribeTopic(s.ctx, name)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
+// configValues retourne la configuration de `topic` sous forme de map
+func (s *TopicsIntegrationSuite) configValues(topic string, names ...string) map[string]string {
+	entries, err := s.client.DescribeTopicConfig(s.ctx, topic, names...)
+	s.Require().NoError(err)
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		values[e.Name] = e.Value
+	}
+	return values
+}
+
+// Test_CreateTopicIsIdempotent : un second appel signale le topic existant sans erreur
+func (s *TopicsIntegrationSuite) Test_CreateTopicIsIdempotent() {
+	s.createTopic("topics-idempotent", nil)
+
+	created, err := s.client.CreateTopic(s.ctx, avro_kafka_config.TopicSpec{Name: "topics-idempotent", Partitions: 3})
+	s.Require().NoError(err)
+	s.Assert().False(created)
+
+	desc, err := s.client.DescribeTopic(s.ctx, "topics-idempotent")
+	s.Require().NoError(err)
+	s.Assert().Len(desc.Partitions, 1, "la configuration d'un topic existant n'est pas modifiée")
+}
+
+// Test_DescribeTopicConfig : valeurs explicites, valeurs par défaut et filtre par nom
+func (s *TopicsIntegrationSuite) Test_DescribeTopicConfig() {
+	s.createTopic("topics-describe", map[string]string{"retention.ms": "3600000"})
+
+	entries, err := s.client.DescribeTopicConfig(s.ctx, "topics-describe", "retention.ms", "cleanup.policy")
+	s.Require().NoError(err)
+	s.Require().Len(entries, 2)
+	s.Assert().Equal("cleanup.policy", entries[0].Name, "entrées triées par nom")
+	s.Assert().True(entries[0].Default)
+	s.Assert().Equal(avro_kafka_config.ConfigEntry{Name: "retention.ms", Value: "3600000"}, entries[1])
+
+	all, err := s.client.DescribeTopicConfig(s.ctx, "topics-describe")
+	s.Require().NoError(err)
+	s.Assert().Greater(len(all), 2)
+
+	_, err = s.client.DescribeTopicConfig(s.ctx, "topics-inconnu")
+	s.Assert().Error(err)
+}
+
+// Test_AlterTopicConfigIsIncremental : les entrées non citées sont conservées
+func (s *TopicsIntegrationSuite) Test_AlterTopicConfigIsIncremental() {
+	s.createTopic("topics-alter", map[string]string{"retention.ms": "3600000", "max.message.bytes": "200000"})
+
+	s.Require().NoError(s.client.AlterTopicConfig(s.ctx, "topics-alter", map[string]string{"cleanup.policy": "compact"}))
+	s.Require().Eventually(func() bool {
+		return s.configValues("topics-alter", "cleanup.policy")["cleanup.policy"] == "compact"
+	}, 10*time.Second, 100*time.Millisecond)
+	values := s.configValues("topics-alter", "retention.ms", "max.message.bytes")
+	s.Assert().Equal("3600000", values["retention.ms"])
+	s.Assert().Equal("200000", values["max.message.bytes"])
+
+	// reset : retour à la valeur par défaut, les autres entrées restent
+	s.Require().NoError(s.client.AlterTopicConfig(s.ctx, "topics-alter", nil, "retention.ms"))
+	s.Require().Eventually(func() bool {
+		entries, err := s.client.DescribeTopicConfig(s.ctx, "topics-alter", "retention.ms")
+		return err == nil && len(entries) == 1 && entries[0].Default
+	}, 10*time.Second, 100*time.Millisecond)
+	s.Assert().Equal("compact", s.configValues("topics-alter", "cleanup.policy")["cleanup.policy"])
+}
+
+// Test_CreatePartitions : ajout de partitions, retrait refusé
+func (s *TopicsIntegrationSuite) Test_CreatePartitions() {
+	s.createTopic("topics-partitions", nil)
+
+	s.Require().NoError(s.client.CreatePartitions(s.ctx, "topics-partitions", 3))
+	s.Require().Eventually(func() bool {
+		desc, err := s.client.DescribeTopic(s.ctx, "topics-partitions")
+		return err == nil && len(desc.Partitions) == 3
+	}, 10*time.Second, 100*time.Millisecond)
+
+	s.Assert().Error(s.client.CreatePartitions(s.ctx, "topics-partitions", 2))
+}
+
+// Exécuter la suite de tests
+func TestTopicsIntegrationSuite(t *testing.T) {
+	suite.Run(t, new(TopicsIntegrationSuite))
+}
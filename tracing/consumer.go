@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/consumer"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware extrait le contexte de trace des headers du message et démarre un
+// span "process" parent du contexte transmis au handler
+func Middleware[T models.AvroEvent](opts ...Option) consumer.Middleware[T] {
+	cfg := newConfig(opts)
+
+	return func(next consumer.Handler[T]) consumer.Handler[T] {
+		return func(ctx context.Context, event T) error {
+			msg, ok := consumer.MessageFromContext(ctx)
+			if !ok {
+				return next(ctx, event)
+			}
+
+			headers := msg.Headers
+			ctx = cfg.propagator.Extract(ctx, HeaderCarrier{Headers: &headers})
+
+			ctx, span := cfg.tracer().Start(ctx, "process "+msg.Topic,
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(
+					semconv.MessagingSystemKafka,
+					semconv.MessagingOperationTypeDeliver,
+					semconv.MessagingOperationName("process"),
+					semconv.MessagingDestinationName(msg.Topic),
+					semconv.MessagingDestinationPartitionID(strconv.Itoa(msg.Partition)),
+					semconv.MessagingKafkaMessageOffset(int(msg.Offset)),
+					semconv.MessagingKafkaMessageKey(string(msg.Key)),
+					semconv.MessagingKafkaConsumerGroup(msg.GroupID),
+					semconv.MessagingMessageBodySize(len(msg.Value)),
+				),
+			)
+			defer span.End()
+
+			err := next(ctx, event)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/producer"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProducerInterceptor crée un span "publish" par message et injecte son contexte
+// dans les headers ; à placer en dernier pour que le span couvre l'envoi uniquement
+func ProducerInterceptor(opts ...Option) producer.Interceptor {
+	return &producerInterceptor{cfg: newConfig(opts)}
+}
+
+type producerInterceptor struct {
+	cfg config
+}
+
+func (i *producerInterceptor) OnSend(ctx context.Context, rec *producer.Record) (context.Context, error) {
+	ctx, _ = i.cfg.tracer().Start(ctx, "publish "+rec.Topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingOperationName("publish"),
+			semconv.MessagingDestinationName(rec.Topic),
+			semconv.MessagingKafkaMessageKey(string(rec.Key)),
+		),
+	)
+	i.cfg.propagator.Inject(ctx, HeaderCarrier{Headers: &rec.Headers})
+	return ctx, nil
+}
+
+func (i *producerInterceptor) OnDelivery(ctx context.Context, rec *producer.Record, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(semconv.MessagingMessageBodySize(len(rec.Value)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
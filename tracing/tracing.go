@@ -0,0 +1,95 @@
+// Package tracing propage le contexte OpenTelemetry à travers les headers Kafka
+// (W3C traceparent/tracestate et baggage) et crée les spans producer/consumer
+// selon les conventions sémantiques OTel pour la messagerie.
+//
+// Côté producer :
+//
+//	producer.NewProducer(ctx, cfg, producer.WithInterceptors(tracing.ProducerInterceptor()))
+//
+// Côté consumer :
+//
+//	consumer.NewConsumer(cfg, consumer.WithMiddleware(tracing.Middleware[models.ModelExample]()))
+package tracing
+
+import (
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/METAVENTUS/metaventus-kafka-adapters/tracing"
+
+// Option personnalise l'instrumentation
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// WithTracerProvider remplace le TracerProvider global
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithPropagator remplace le propagateur par défaut (TraceContext + Baggage)
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = p
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+func (c config) tracer() trace.Tracer {
+	return c.tracerProvider.Tracer(instrumentationName)
+}
+
+// HeaderCarrier adapte des headers Kafka à propagation.TextMapCarrier
+type HeaderCarrier struct {
+	Headers *[]kafka.Header
+}
+
+var _ propagation.TextMapCarrier = HeaderCarrier{}
+
+// Get retourne la valeur du header `key`
+func (c HeaderCarrier) Get(key string) string {
+	for _, h := range *c.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set ajoute ou remplace le header `key`
+func (c HeaderCarrier) Set(key, value string) {
+	for i, h := range *c.Headers {
+		if h.Key == key {
+			(*c.Headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.Headers = append(*c.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys retourne le nom de tous les headers
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.Headers))
+	for _, h := range *c.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
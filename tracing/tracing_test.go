@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/METAVENTUS/metaventus-kafka-adapters/consumer"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/models"
+	"github.com/METAVENTUS/metaventus-kafka-adapters/producer"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPropagationFromProducerToConsumer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	member, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+
+	// Publication : le span producer est injecté dans les headers
+	rec := &producer.Record{Topic: "orders", Key: []byte("42"), Event: models.ModelExample{ID: "42"}}
+	interceptor := ProducerInterceptor(WithTracerProvider(tp))
+	sendCtx, err := interceptor.OnSend(ctx, rec)
+	require.NoError(t, err)
+	interceptor.OnDelivery(sendCtx, rec, nil)
+
+	carrier := HeaderCarrier{Headers: &rec.Headers}
+	assert.NotEmpty(t, carrier.Get("traceparent"))
+	assert.Contains(t, carrier.Get("baggage"), "tenant=acme")
+
+	// Consommation : le span consumer a pour parent le span producer
+	var handlerSpan trace.SpanContext
+	var handlerBaggage string
+	handler := Middleware[models.ModelExample](WithTracerProvider(tp))(func(ctx context.Context, _ models.ModelExample) error {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		handlerBaggage = baggage.FromContext(ctx).Member("tenant").Value()
+		return nil
+	})
+
+	msgCtx := consumer.ContextWithMessage(context.Background(), consumer.Message{
+		Message: kafka.Message{Topic: "orders", Partition: 3, Offset: 17, Key: rec.Key, Headers: rec.Headers},
+		GroupID: "billing",
+	})
+	require.NoError(t, handler(msgCtx, models.ModelExample{}))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	producerSpan, consumerSpan := spans[0], spans[1]
+
+	assert.Equal(t, trace.SpanKindProducer, producerSpan.SpanKind)
+	assert.Equal(t, "publish orders", producerSpan.Name)
+	assert.Equal(t, trace.SpanKindConsumer, consumerSpan.SpanKind)
+	assert.Equal(t, "process orders", consumerSpan.Name)
+	assert.Equal(t, producerSpan.SpanContext.TraceID(), consumerSpan.SpanContext.TraceID())
+	assert.Equal(t, producerSpan.SpanContext.SpanID(), consumerSpan.Parent.SpanID())
+	assert.Equal(t, consumerSpan.SpanContext.SpanID(), handlerSpan.SpanID())
+	assert.Equal(t, "acme", handlerBaggage)
+
+	attrs := attribute.NewSet(consumerSpan.Attributes...)
+	group, _ := attrs.Value("messaging.kafka.consumer.group")
+	assert.Equal(t, "billing", group.AsString())
+	partition, _ := attrs.Value("messaging.destination.partition.id")
+	assert.Equal(t, "3", partition.AsString())
+	offset, _ := attrs.Value("messaging.kafka.message.offset")
+	assert.Equal(t, int64(17), offset.AsInt64())
+}